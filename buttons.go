@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -23,10 +25,25 @@ type ButtonPoller struct {
 // Poll returns a set of Button flags indicating which buttons
 // were pressed when the call was made. Poll does not block.
 func (b *ButtonPoller) Poll() (Button, error) {
+	if sess != nil {
+		s, err := sess.do(buttonsOp, filepath.Join(prefix, ButtonPath), "", func() (string, error) {
+			pressed, err := b.poll()
+			return strconv.Itoa(int(pressed)), err
+		})
+		if err != nil {
+			return 0, err
+		}
+		pressed, err := strconv.Atoi(s)
+		return Button(pressed), err
+	}
+	return b.poll()
+}
+
+func (b *ButtonPoller) poll() (Button, error) {
 	if b.buf == nil {
 		b.buf = make([]byte, keyBufLen)
 	}
-	ev, err := os.Open(ButtonPath)
+	ev, err := os.Open(filepath.Join(prefix, ButtonPath))
 	if err != nil {
 		return 0, fmt.Errorf("ev3dev: failed to open button event device: %v", err)
 	}
@@ -62,9 +79,15 @@ type ButtonWaiter struct {
 
 // NewButtonWaiter returns a ButtonWaiter.
 func NewButtonWaiter() (*ButtonWaiter, error) {
-	ev, err := os.Open(ButtonPath)
-	if err != nil {
-		return nil, fmt.Errorf("ev3dev: failed to open button event device: %v", err)
+	path := filepath.Join(prefix, ButtonPath)
+	s := sess
+	var ev *os.File
+	if s == nil || !s.replaying() {
+		var err error
+		ev, err = os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("ev3dev: failed to open button event device: %v", err)
+		}
 	}
 
 	c := make(chan ButtonEvent)
@@ -80,7 +103,22 @@ func NewButtonWaiter() (*ButtonWaiter, error) {
 				close(c)
 				return
 			default:
-				_, err := io.ReadFull(ev, buf[:])
+				var err error
+				if s == nil {
+					_, err = io.ReadFull(ev, buf[:])
+				} else {
+					var e string
+					e, err = s.do(buttonEventOp, path, "", func() (string, error) {
+						_, err := io.ReadFull(ev, buf[:])
+						return string(buf[:]), err
+					})
+					if err == errReplayEnd {
+						<-b.done
+						close(c)
+						return
+					}
+					copy(buf[:], e)
+				}
 				if err != nil {
 					c <- ButtonEvent{Err: err}
 					continue
@@ -111,6 +149,10 @@ func (b *ButtonWaiter) Close() error {
 	default:
 		close(b.done)
 		b.wg.Wait()
+		if b.f == nil {
+			// The events are replayed.
+			return nil
+		}
 		return b.f.Close()
 	}
 }
@@ -5,6 +5,9 @@
 package ev3dev
 
 import (
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -313,3 +316,32 @@ func TestButtonEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestButtonRoot(t *testing.T) {
+	root, err := ioutil.TempDir("", "ev3dev-buttons")
+	if err != nil {
+		t.Fatalf("failed to make temporary directory: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	saved := Root()
+	SetRoot(root)
+	defer SetRoot(saved)
+	if got := Root(); got != root {
+		t.Errorf("unexpected root: got:%q want:%q", got, root)
+	}
+
+	var b ButtonPoller
+	_, err = b.Poll()
+	if err == nil || !strings.Contains(err.Error(), "failed to open") {
+		t.Errorf("expected open error for missing button device: got:%v", err)
+	}
+
+	// A regular file at the button path under the root
+	// is opened, but cannot be used as an event device.
+	writeTree(t, root, map[string]string{ButtonPath: ""})
+	_, err = b.Poll()
+	if err == nil || !strings.Contains(err.Error(), "ioctl") {
+		t.Errorf("expected ioctl error for button device under root: got:%v", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// diagnose is whether calls skipped
+// due to a sticky error are recorded.
+var diagnose bool
+
+// maxSkipped is the maximum number of skipped
+// calls recorded for a sticky error.
+const maxSkipped = 64
+
+// SetChainDiagnostics sets whether calls to methods of device handles that
+// are skipped because the handle holds an error are recorded. When enabled,
+// each skipped call is recorded with its method, arguments and call site, and
+// the error returned by the handle's Err method describes the skipped calls
+// after the original error. The original error is returned by the Cause and
+// Unwrap methods of the returned error.
+//
+// SetChainDiagnostics is not safe for concurrent use with other functions in
+// the package.
+func SetChainDiagnostics(on bool) {
+	diagnose = on
+}
+
+// chainError is a sticky error and the calls
+// that were skipped after it was set.
+type chainError struct {
+	err     error
+	skipped []skippedCall
+
+	// dropped is the number of skipped calls
+	// not recorded after maxSkipped.
+	dropped int
+}
+
+// skippedCall is a method call skipped due to a sticky error.
+type skippedCall struct {
+	args []interface{}
+
+	stack
+}
+
+// skipped returns the sticky error err with a record of the calling
+// method being skipped with the given arguments.
+func skipped(err error, args ...interface{}) error {
+	c, ok := err.(*chainError)
+	if !ok {
+		c = &chainError{err: err}
+	}
+	if len(c.skipped) < maxSkipped {
+		c.skipped = append(c.skipped, skippedCall{args: args, stack: callers()})
+	} else {
+		c.dropped++
+	}
+	return c
+}
+
+func (e *chainError) Error() string {
+	var buf strings.Builder
+	buf.WriteString(e.err.Error())
+	buf.WriteString("; skipped: ")
+	for i, c := range e.skipped {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(c.String())
+	}
+	if e.dropped != 0 {
+		fmt.Fprintf(&buf, " and %d more", e.dropped)
+	}
+	return buf.String()
+}
+
+func (e *chainError) Format(fs fmt.State, c rune) {
+	switch c {
+	case 'v':
+		if fs.Flag('+') {
+			fmt.Fprintf(fs, "%+v", e.err)
+			for _, c := range e.skipped {
+				fmt.Fprintf(fs, "skipped %s\n", c.call())
+				c.stack.writeTo(fs)
+			}
+			if e.dropped != 0 {
+				fmt.Fprintf(fs, "skipped %d more\n", e.dropped)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(fs, e.Error())
+	case 'q':
+		fmt.Fprintf(fs, "%q", e.Error())
+	default:
+		fmt.Fprintf(fs, "%"+string(c), e.Error())
+	}
+}
+
+func (e *chainError) Cause() error  { return e.err }
+func (e *chainError) Unwrap() error { return e.err }
+
+// String returns the skipped call and its call site.
+func (c skippedCall) String() string {
+	_, site := c.frames()
+	if site.PC == 0 {
+		return c.call() + " at <unknown caller>"
+	}
+	return fmt.Sprintf("%s at %s:%d %s", c.call(), filepath.Base(site.File), site.Line, site.Function)
+}
+
+// frames returns the frames of the skipped method and its caller. The
+// stack is expanded with runtime.CallersFrames so that the call site is
+// correct when the method has been inlined.
+func (c skippedCall) frames() (method, site runtime.Frame) {
+	frames := runtime.CallersFrames(c.stack)
+	method, more := frames.Next()
+	if more {
+		site, _ = frames.Next()
+	}
+	return method, site
+}
+
+// call returns the skipped method call with its arguments.
+func (c skippedCall) call() string {
+	name := "<unknown method>"
+	if method, _ := c.frames(); method.Function != "" {
+		name = method.Function
+		// Remove the package path.
+		name = name[strings.LastIndex(name, "/")+1:]
+		name = name[strings.Index(name, ".")+1:]
+	}
+	args := make([]string, len(c.args))
+	for i, a := range c.args {
+		if s, ok := a.(string); ok {
+			args[i] = fmt.Sprintf("%q", s)
+		} else {
+			args[i] = fmt.Sprint(a)
+		}
+	}
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
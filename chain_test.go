@@ -0,0 +1,63 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestChainDiagnostics(t *testing.T) {
+	motor := filepath.Join(TachoMotorPath, "motor0")
+	_, done := fakeRoot(t, map[string]string{
+		filepath.Join(motor, address):     "ev3-ports:outA\n",
+		filepath.Join(motor, driverName):  "lego-ev3-l-motor\n",
+		filepath.Join(motor, countPerRot): "360\n",
+		filepath.Join(motor, maxSpeed):    "1050\n",
+		filepath.Join(motor, commands):    "run-forever stop reset\n",
+		filepath.Join(motor, stopActions): "coast brake hold\n",
+	})
+	defer done()
+	defer SetChainDiagnostics(false)
+
+	m, err := TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = m.SetDutyCycleSetpoint(200).Command("run-forever").Err()
+	if _, ok := err.(valueOutOfRangeError); !ok {
+		t.Errorf("unexpected error type without diagnostics: %T", err)
+	}
+
+	SetChainDiagnostics(true)
+	err = m.SetDutyCycleSetpoint(200).Command("run-forever").SetStopAction("hold").Err()
+	if _, ok := cause(err).(valueOutOfRangeError); !ok {
+		t.Errorf("unexpected error cause with diagnostics: %T", cause(err))
+	}
+	want := regexp.MustCompile(`; skipped: \(\*TachoMotor\)\.Command\("run-forever"\) at chain_test\.go:\d+ .*TestChainDiagnostics, \(\*TachoMotor\)\.SetStopAction\("hold"\) at chain_test\.go:\d+ .*TestChainDiagnostics$`)
+	if !want.MatchString(err.Error()) {
+		t.Errorf("unexpected error string: %s", err)
+	}
+	trace := fmt.Sprintf("%+v", err)
+	if !strings.Contains(trace, `skipped (*TachoMotor).SetStopAction("hold")`) {
+		t.Errorf("unexpected error trace:\n%s", trace)
+	}
+	if m.Err() != nil {
+		t.Error("expected error state to be cleared")
+	}
+
+	m.SetDutyCycleSetpoint(200)
+	for i := 0; i < maxSkipped+2; i++ {
+		m.SetSpeedSetpoint(i)
+	}
+	err = m.Err()
+	if !strings.HasSuffix(err.Error(), " and 2 more") {
+		t.Errorf("unexpected error string for dropped calls: %s", err)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import "time"
+
+// Clock is a source of time for time-dependent operations.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Sleep pauses the calling goroutine
+	// for at least the duration d.
+	Sleep(d time.Duration)
+
+	// After returns a channel that receives
+	// the current time after the duration d.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock provided by the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// clock is the package clock.
+var clock = SystemClock
+
+// SetClock sets the clock used by the package's time-dependent operations.
+// If c is nil, the clock is set to SystemClock, which is the default.
+//
+// The clock is used for the timeouts and polling intervals of Wait, WaitContext
+// and WaitDevices, and for the times reported by ReadAttrs, TachoMotor.Status
+// and AttributeWatcher events. When the clock is not SystemClock, Wait and
+// WaitDevices read motor states at intervals of the clock rather than using
+// poll(2). Background polling by AttributeWatcher and HotplugWatcher always
+// uses real time, and context deadlines are not affected by the clock.
+//
+// SetClock is not safe for concurrent use with other functions in the package.
+func SetClock(c Clock) {
+	if c == nil {
+		c = SystemClock
+	}
+	clock = c
+}
+
+// CurrentClock returns the clock set by SetClock.
+func CurrentClock() Clock { return clock }
+
+// realTime returns whether the package clock is the system clock.
+func realTime() bool { return clock == SystemClock }
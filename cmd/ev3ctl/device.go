@@ -0,0 +1,169 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+)
+
+// device is a device selected on the command line. Device is nil for
+// LEDs and power supplies, which are accessed through their sysfs files.
+type device struct {
+	ev3dev.Device
+	info ev3dev.DeviceInfo
+}
+
+// open returns the device specified by spec.
+func open(spec string) (*device, error) {
+	inv, err := ev3dev.Inventory()
+	if err != nil {
+		return nil, err
+	}
+	var match []ev3dev.DeviceInfo
+	for _, d := range inv {
+		if spec == d.Class+"/"+d.Name || spec == d.Name || isAddress(spec, d.Address) {
+			match = append(match, d)
+		}
+	}
+	if len(match) > 1 {
+		// Prefer the device attached to a
+		// port over the port itself.
+		var attached []ev3dev.DeviceInfo
+		for _, d := range match {
+			if d.Class != ev3dev.LegoPortClass {
+				attached = append(attached, d)
+			}
+		}
+		if len(attached) != 0 {
+			match = attached
+		}
+	}
+	switch len(match) {
+	case 0:
+		return nil, fmt.Errorf("no device matching %q", spec)
+	case 1:
+	default:
+		var names []string
+		for _, d := range match {
+			names = append(names, d.Class+"/"+d.Name)
+		}
+		return nil, fmt.Errorf("ambiguous device %q matches %s", spec, strings.Join(names, ", "))
+	}
+
+	dev := device{info: match[0]}
+	switch dev.info.Class {
+	case ev3dev.LEDClass, ev3dev.PowerSupplyClass:
+		return &dev, nil
+	}
+	dev.Device, err = dev.info.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &dev, nil
+}
+
+// isAddress returns whether spec refers to the port address addr, either
+// in full or by the port name following the colon.
+func isAddress(spec, addr string) bool {
+	if addr == "" {
+		return false
+	}
+	return spec == addr || strings.HasSuffix(addr, ":"+spec)
+}
+
+// classPaths holds the sysfs paths for devices that do not have a handle
+// implementing ev3dev.Device.
+var classPaths = map[string]string{
+	ev3dev.LEDClass:         ev3dev.LEDPath,
+	ev3dev.PowerSupplyClass: ev3dev.PowerSupplyPath,
+}
+
+// read returns the value of the named attribute.
+func (d *device) read(attr string) (string, error) {
+	if d.Device != nil {
+		return ev3dev.ReadAttr(d.Device, attr)
+	}
+	a, ok := ev3dev.AttrFor(d.info.Class, attr)
+	if !ok {
+		return "", fmt.Errorf("no attribute %s for %s/%s", attr, d.info.Class, d.info.Name)
+	}
+	if a.Access&ev3dev.Read == 0 {
+		return "", fmt.Errorf("attribute %s of %s/%s is not readable", attr, d.info.Class, d.info.Name)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(ev3dev.Root(), classPaths[d.info.Class], d.info.Name, attr))
+	return strings.TrimSuffix(string(b), "\n"), err
+}
+
+// readAll returns the values of the named attributes and the time they
+// were read.
+func (d *device) readAll(attrs []string) ([]string, time.Time, error) {
+	if d.Device != nil {
+		return ev3dev.ReadAttrs(d.Device, attrs...)
+	}
+	t := time.Now()
+	vals := make([]string, len(attrs))
+	for i, a := range attrs {
+		var err error
+		vals[i], err = d.read(a)
+		if err != nil {
+			return nil, t, err
+		}
+	}
+	return vals, t, nil
+}
+
+// write writes value to the named attribute.
+func (d *device) write(attr, value string) error {
+	if d.Device == nil {
+		return fmt.Errorf("cannot set attributes of %s/%s", d.info.Class, d.info.Name)
+	}
+	return ev3dev.WriteAttr(d.Device, attr, value)
+}
+
+// defaultAttrs returns the attributes read by the read command when none
+// are specified.
+func (d *device) defaultAttrs() []string {
+	switch dev := d.Device.(type) {
+	case *ev3dev.Sensor:
+		attrs := make([]string, dev.NumValues())
+		for i := range attrs {
+			attrs[i] = "value" + strconv.Itoa(i)
+		}
+		return attrs
+	case *ev3dev.TachoMotor, *ev3dev.LinearActuator:
+		return []string{"position", "speed", "state"}
+	case *ev3dev.ServoMotor:
+		return []string{"position_sp", "state"}
+	case *ev3dev.DCMotor:
+		return []string{"duty_cycle", "state"}
+	case *ev3dev.LegoPort:
+		return []string{"status"}
+	}
+	switch d.info.Class {
+	case ev3dev.LEDClass:
+		return []string{"brightness"}
+	case ev3dev.PowerSupplyClass:
+		return []string{"voltage_now", "current_now"}
+	}
+	return nil
+}
+
+// cause returns the underlying cause of err.
+func cause(err error) error {
+	for {
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			return err
+		}
+		err = c.Cause()
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// ev3ctl inspects and drives ev3dev devices from the command line.
+//
+// Usage:
+//
+//  ev3ctl [-prefix root] <command> [arguments]
+//
+// The commands are:
+//
+//  list                                   list all devices
+//  show <device>                          show all attributes of a device
+//  get <device> <attr>                    print the value of an attribute
+//  set <device> <attr> <value>            set the value of an attribute
+//  run <motor> <command> [attr=value...]  set attributes and issue a motor command
+//  mode <sensor|port> <mode>              set the mode of a sensor or port
+//  read [-i interval] [-n count] <device> [attr...]
+//                                         read attribute values continuously
+//  led <led> <brightness|trigger>         set the brightness or trigger of an LED
+//  tone [-d duration] [-dev path] <freq>  play a tone
+//
+// Devices are specified by sysfs class and name, for example tacho-motor/motor0
+// or leds/led0:red:brick-status, by port address, for example ev3-ports:outA or
+// outA, or by name alone when the name is unique, for example led0:red:brick-status.
+// When a port address matches both a lego-port and the device attached to it,
+// the attached device is used.
+//
+// The -prefix flag specifies an alternative filesystem root, allowing ev3ctl
+// to be used with a fake sysfs tree. The root is also prepended to the
+// default tone device path.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+)
+
+func main() {
+	os.Exit(ev3ctl(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// commands holds the ev3ctl commands. Each command writes its output to w.
+var commands = map[string]func(w io.Writer, args []string) error{
+	"list": list,
+	"show": show,
+	"get":  get,
+	"set":  set,
+	"run":  run,
+	"mode": mode,
+	"read": read,
+	"led":  led,
+	"tone": tone,
+}
+
+// ev3ctl runs the command line in args, writing output to stdout and
+// diagnostics to stderr, and returns the program's exit status.
+func ev3ctl(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ev3ctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	root := flags.String("prefix", "", "specify an alternative filesystem root")
+	flags.Usage = func() { usage(flags) }
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		usage(flags)
+		return 2
+	}
+	ev3dev.SetRoot(*root)
+
+	name, args := flags.Arg(0), flags.Args()[1:]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "ev3ctl: unknown command %q\n", name)
+		usage(flags)
+		return 2
+	}
+	err = cmd(stdout, args)
+	if err != nil {
+		fmt.Fprintf(stderr, "ev3ctl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func usage(flags *flag.FlagSet) {
+	fmt.Fprint(flags.Output(), `usage: ev3ctl [-prefix root] <command> [arguments]
+
+commands:
+  list                                   list all devices
+  show <device>                          show all attributes of a device
+  get <device> <attr>                    print the value of an attribute
+  set <device> <attr> <value>            set the value of an attribute
+  run <motor> <command> [attr=value...]  set attributes and issue a motor command
+  mode <sensor|port> <mode>              set the mode of a sensor or port
+  read [-i interval] [-n count] <device> [attr...]
+                                         read attribute values continuously
+  led <led> <brightness|trigger>         set the brightness or trigger of an LED
+  tone [-d duration] [-dev path] <freq>  play a tone
+
+flags:
+`)
+	flags.PrintDefaults()
+}
+
+// nargs returns an error if args does not have between min and max
+// elements. If max is negative, there is no upper limit.
+func nargs(cmd string, args []string, min, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return fmt.Errorf("wrong number of arguments for %s", cmd)
+	}
+	return nil
+}
+
+func list(w io.Writer, args []string) error {
+	err := nargs("list", args, 0, 0)
+	if err != nil {
+		return err
+	}
+	inv, err := ev3dev.Inventory()
+	if err != nil {
+		return err
+	}
+	for _, d := range inv {
+		fmt.Fprintf(w, "%s/%s", d.Class, d.Name)
+		if d.Address != "" {
+			fmt.Fprintf(w, "\t%s\t%s", d.Address, d.Driver)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func show(w io.Writer, args []string) error {
+	err := nargs("show", args, 1, 1)
+	if err != nil {
+		return err
+	}
+	dev, err := open(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s/%s\n", dev.info.Class, dev.info.Name)
+	for _, a := range ev3dev.Schema(dev.info.Class) {
+		if a.Access&ev3dev.Read == 0 || a.Type == ev3dev.BinaryAttr {
+			continue
+		}
+		v, err := dev.read(a.Name)
+		if err != nil {
+			// Not all devices provide every
+			// attribute in their class.
+			if os.IsNotExist(cause(err)) {
+				continue
+			}
+			v = fmt.Sprintf("error: %v", err)
+		}
+		if a.Type == ev3dev.UeventAttr {
+			v = strings.Replace(v, "\n", " ", -1)
+		}
+		if a.Units != "" && err == nil {
+			v += " " + a.Units
+		}
+		fmt.Fprintf(w, "  %-24s %-2s %s\n", a.Name, a.Access, v)
+	}
+	return nil
+}
+
+func get(w io.Writer, args []string) error {
+	err := nargs("get", args, 2, 2)
+	if err != nil {
+		return err
+	}
+	dev, err := open(args[0])
+	if err != nil {
+		return err
+	}
+	v, err := dev.read(args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, v)
+	return nil
+}
+
+func set(w io.Writer, args []string) error {
+	err := nargs("set", args, 3, 3)
+	if err != nil {
+		return err
+	}
+	dev, err := open(args[0])
+	if err != nil {
+		return err
+	}
+	return dev.write(args[1], args[2])
+}
+
+func run(w io.Writer, args []string) error {
+	err := nargs("run", args, 2, -1)
+	if err != nil {
+		return err
+	}
+	dev, err := open(args[0])
+	if err != nil {
+		return err
+	}
+	for _, kv := range args[2:] {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			return fmt.Errorf("invalid attribute assignment %q", kv)
+		}
+		err = dev.write(kv[:i], kv[i+1:])
+		if err != nil {
+			return err
+		}
+	}
+	comm := args[1]
+	switch d := dev.Device.(type) {
+	case *ev3dev.TachoMotor:
+		return d.Command(comm).Err()
+	case *ev3dev.LinearActuator:
+		return d.Command(comm).Err()
+	case *ev3dev.ServoMotor:
+		return d.Command(comm).Err()
+	case *ev3dev.DCMotor:
+		return d.Command(comm).Err()
+	default:
+		return fmt.Errorf("%s/%s is not a motor", dev.info.Class, dev.info.Name)
+	}
+}
+
+func mode(w io.Writer, args []string) error {
+	err := nargs("mode", args, 2, 2)
+	if err != nil {
+		return err
+	}
+	dev, err := open(args[0])
+	if err != nil {
+		return err
+	}
+	switch d := dev.Device.(type) {
+	case *ev3dev.Sensor:
+		return d.SetMode(args[1]).Err()
+	case *ev3dev.LegoPort:
+		return d.SetMode(args[1]).Err()
+	default:
+		return fmt.Errorf("%s/%s is not a sensor or port", dev.info.Class, dev.info.Name)
+	}
+}
+
+func read(w io.Writer, args []string) error {
+	flags := flag.NewFlagSet("read", flag.ContinueOnError)
+	interval := flags.Duration("i", 100*time.Millisecond, "specify the read interval")
+	count := flags.Int("n", 0, "specify the number of reads (0 reads until interrupted)")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	err = nargs("read", args, 1, -1)
+	if err != nil {
+		return err
+	}
+	dev, err := open(args[0])
+	if err != nil {
+		return err
+	}
+	attrs := args[1:]
+	if len(attrs) == 0 {
+		attrs = dev.defaultAttrs()
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for i := 0; *count <= 0 || i < *count; i++ {
+		if i != 0 {
+			<-ticker.C
+		}
+		vals, t, err := dev.readAll(attrs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, t.Format("15:04:05.000"))
+		for j, a := range attrs {
+			fmt.Fprintf(w, " %s=%s", a, vals[j])
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func led(w io.Writer, args []string) error {
+	err := nargs("led", args, 2, 2)
+	if err != nil {
+		return err
+	}
+	dev, err := open(args[0])
+	if err != nil {
+		return err
+	}
+	if dev.info.Class != ev3dev.LEDClass {
+		return fmt.Errorf("%s/%s is not an LED", dev.info.Class, dev.info.Name)
+	}
+	l := &ev3dev.LED{Name: ev3dev.LEDName(dev.info.Name)}
+	b, err := strconv.Atoi(args[1])
+	if err == nil {
+		return l.SetBrightness(b).Err()
+	}
+	return l.SetTrigger(args[1]).Err()
+}
+
+// soundPath is the path to the ev3 sound events
+// relative to the filesystem root.
+const soundPath = "/dev/input/by-path/platform-snd-legoev3-event"
+
+func tone(w io.Writer, args []string) error {
+	flags := flag.NewFlagSet("tone", flag.ContinueOnError)
+	dur := flags.Duration("d", 200*time.Millisecond, "specify the tone duration")
+	path := flags.String("dev", filepath.Join(ev3dev.Root(), soundPath), "specify the sound event device")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	args = flags.Args()
+	err = nargs("tone", args, 1, 1)
+	if err != nil {
+		return err
+	}
+	freq, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid frequency: %v", err)
+	}
+
+	s := ev3dev.NewSpeaker(*path)
+	err = s.Init()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	err = s.Tone(uint32(freq))
+	if err != nil {
+		return err
+	}
+	time.Sleep(*dur)
+	return s.Tone(0)
+}
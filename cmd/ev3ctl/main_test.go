@@ -0,0 +1,216 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ev3go/ev3dev"
+	"github.com/ev3go/ev3dev/ev3devtest"
+)
+
+// attrCheck is an expected attribute value of a fake device.
+type attrCheck struct {
+	class, name, attr string
+	want              string
+}
+
+var ev3ctlTests = []struct {
+	name string
+	args []string
+
+	wantCode  int
+	wantOut   string
+	wantOutRE string
+	wantErr   string // $ROOT is replaced by the fake filesystem root.
+	wantAttrs []attrCheck
+}{
+	{
+		name:     "no command",
+		args:     nil,
+		wantCode: 2,
+		wantErr:  "usage: ev3ctl [-prefix root] <command> [arguments]",
+	},
+	{
+		name:     "unknown command",
+		args:     []string{"jump"},
+		wantCode: 2,
+		wantErr:  "ev3ctl: unknown command \"jump\"\nusage: ev3ctl",
+	},
+	{
+		name: "list",
+		args: []string{"list"},
+		wantOut: "lego-port/port0\tev3-ports:outA\tlegoev3-output-port\n" +
+			"lego-sensor/sensor0\tev3-ports:in1\tlego-ev3-color\n" +
+			"tacho-motor/motor0\tev3-ports:outA\tlego-ev3-l-motor\n" +
+			"leds/led0:red:brick-status\n",
+	},
+	{
+		name:     "list arguments",
+		args:     []string{"list", "outA"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: wrong number of arguments for list\n",
+	},
+	{
+		name: "show",
+		args: []string{"show", "led0:red:brick-status"},
+		wantOut: "leds/led0:red:brick-status\n" +
+			"  brightness               rw 0\n" +
+			"  delay_off                rw 0 ms\n" +
+			"  delay_on                 rw 0 ms\n" +
+			"  max_brightness           r  255\n" +
+			"  trigger                  rw [none] timer heartbeat default-on\n" +
+			"  uevent                   r  \n",
+	},
+	{
+		name:    "get by port name",
+		args:    []string{"get", "outA", "driver_name"},
+		wantOut: "lego-ev3-l-motor\n",
+	},
+	{
+		name:    "get by address",
+		args:    []string{"get", "ev3-ports:in1", "mode"},
+		wantOut: "COL-REFLECT\n",
+	},
+	{
+		name:    "get by class and name",
+		args:    []string{"get", "lego-port/port0", "status"},
+		wantOut: "tacho-motor\n",
+	},
+	{
+		name:     "get missing attribute argument",
+		args:     []string{"get", "outA"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: wrong number of arguments for get\n",
+	},
+	{
+		name:     "get unknown device",
+		args:     []string{"get", "outB", "mode"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: no device matching \"outB\"\n",
+	},
+	{
+		name:      "set",
+		args:      []string{"set", "outA", "speed_sp", "300"},
+		wantAttrs: []attrCheck{{ev3dev.TachoMotorClass, "motor0", "speed_sp", "300"}},
+	},
+	{
+		name:     "set read only attribute",
+		args:     []string{"set", "outA", "speed", "300"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: ev3dev: attribute speed is not writable\n",
+	},
+	{
+		name: "run",
+		args: []string{"run", "outA", "run-forever", "speed_sp=200", "stop_action=brake"},
+		wantAttrs: []attrCheck{
+			{ev3dev.TachoMotorClass, "motor0", "speed_sp", "200"},
+			{ev3dev.TachoMotorClass, "motor0", "stop_action", "brake"},
+			{ev3dev.TachoMotorClass, "motor0", "command", "run-forever"},
+		},
+	},
+	{
+		name:     "run invalid assignment",
+		args:     []string{"run", "outA", "run-forever", "speed_sp"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: invalid attribute assignment \"speed_sp\"\n",
+	},
+	{
+		name:     "run sensor",
+		args:     []string{"run", "in1", "run-forever"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: lego-sensor/sensor0 is not a motor\n",
+	},
+	{
+		name:      "mode",
+		args:      []string{"mode", "in1", "COL-COLOR"},
+		wantAttrs: []attrCheck{{ev3dev.SensorClass, "sensor0", "mode", "COL-COLOR"}},
+	},
+	{
+		name:     "mode motor",
+		args:     []string{"mode", "outA", "COL-COLOR"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: tacho-motor/motor0 is not a sensor or port\n",
+	},
+	{
+		name:      "led brightness",
+		args:      []string{"led", "led0:red:brick-status", "128"},
+		wantAttrs: []attrCheck{{ev3dev.LEDClass, "led0:red:brick-status", "brightness", "128"}},
+	},
+	{
+		name:     "led motor",
+		args:     []string{"led", "outA", "128"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: tacho-motor/motor0 is not an LED\n",
+	},
+	{
+		name:      "read",
+		args:      []string{"read", "-n", "1", "outA"},
+		wantOutRE: `^\d\d:\d\d:\d\d\.\d{3} position=0 speed=0 state=\n$`,
+	},
+	{
+		name:      "read attributes",
+		args:      []string{"read", "-n", "2", "-i", "1ms", "in1", "mode", "value0"},
+		wantOutRE: `^(\d\d:\d\d:\d\d\.\d{3} mode=COL-REFLECT value0=0\n){2}$`,
+	},
+	{
+		name:     "tone device under root",
+		args:     []string{"tone", "440"},
+		wantCode: 1,
+		wantErr:  "open " + filepath.Join("$ROOT", soundPath) + ": no such file or directory\n",
+	},
+	{
+		name:     "tone invalid frequency",
+		args:     []string{"tone", "-d", "1ms", "high"},
+		wantCode: 1,
+		wantErr:  "ev3ctl: invalid frequency: ",
+	},
+}
+
+func TestEV3Ctl(t *testing.T) {
+	for _, test := range ev3ctlTests {
+		t.Run(test.name, func(t *testing.T) {
+			b := ev3devtest.NewBrick()
+			b.AddPort("ev3-ports:outA", "legoev3-output-port", "tacho-motor")
+			b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor")
+			b.AddSensor("ev3-ports:in1", "lego-ev3-color", "COL-REFLECT", "COL-COLOR")
+			b.AddLED("led0:red:brick-status")
+			err := b.Start("")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer b.Close()
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-prefix", b.Root()}, test.args...)
+			code := ev3ctl(args, &stdout, &stderr)
+			if code != test.wantCode {
+				t.Errorf("unexpected exit status: got:%d want:%d\nstderr:%s", code, test.wantCode, &stderr)
+			}
+			switch {
+			case test.wantOutRE != "":
+				if !regexp.MustCompile(test.wantOutRE).MatchString(stdout.String()) {
+					t.Errorf("unexpected output: got:%q want match:%q", &stdout, test.wantOutRE)
+				}
+			case stdout.String() != test.wantOut:
+				t.Errorf("unexpected output:\ngot: %q\nwant:%q", &stdout, test.wantOut)
+			}
+			wantErr := strings.Replace(test.wantErr, "$ROOT", b.Root(), -1)
+			if wantErr == "" && stderr.Len() != 0 || !strings.Contains(stderr.String(), wantErr) {
+				t.Errorf("unexpected diagnostic output:\ngot: %q\nwant:%q", &stderr, wantErr)
+			}
+			for _, c := range test.wantAttrs {
+				got := b.Device(c.class, c.name).Attr(c.attr)
+				if got != c.want {
+					t.Errorf("unexpected %s value for %s/%s: got:%q want:%q", c.attr, c.class, c.name, got, c.want)
+				}
+			}
+		})
+	}
+}
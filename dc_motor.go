@@ -20,6 +20,10 @@ type DCMotor struct {
 	driver                string
 	commands, stopActions []string
 
+	// tx holds the writes staged
+	// by a Transaction.
+	tx *transaction
+
 	err error
 }
 
@@ -125,6 +129,9 @@ func (m *DCMotor) Commands() []string {
 // Command issues a command to the DCMotor.
 func (m *DCMotor) Command(comm string) *DCMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, comm)
+		}
 		return m
 	}
 	ok := false
@@ -136,9 +143,12 @@ func (m *DCMotor) Command(comm string) *DCMotor {
 	}
 	if !ok {
 		m.err = newInvalidValueError(m, command, "", comm, m.Commands())
+		if logger != nil {
+			logOp(m, "command", LogInfo, command, comm, m.err)
+		}
 		return m
 	}
-	m.err = setAttributeOf(m, command, comm)
+	m.err = setLoggedAttributeOf(m, "command", LogInfo, command, comm)
 	return m
 }
 
@@ -155,6 +165,9 @@ func (m *DCMotor) DutyCycleSetpoint() (int, error) {
 // SetDutyCycleSetpoint sets the duty cycle setpoint value for the DCMotor
 func (m *DCMotor) SetDutyCycleSetpoint(sp int) *DCMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < -100 || 100 < sp {
@@ -174,6 +187,9 @@ func (m *DCMotor) Polarity() (Polarity, error) {
 // SetPolarity sets the polarity of the DCMotor
 func (m *DCMotor) SetPolarity(p Polarity) *DCMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, p)
+		}
 		return m
 	}
 	if p != Normal && p != Inversed {
@@ -192,6 +208,9 @@ func (m *DCMotor) RampUpSetpoint() (time.Duration, error) {
 // SetRampUpSetpoint sets the ramp up setpoint value for the DCMotor.
 func (m *DCMotor) SetRampUpSetpoint(sp time.Duration) *DCMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 || 10*time.Second < sp {
@@ -210,6 +229,9 @@ func (m *DCMotor) RampDownSetpoint() (time.Duration, error) {
 // SetRampDownSetpoint sets the ramp down setpoint value for the DCMotor.
 func (m *DCMotor) SetRampDownSetpoint(sp time.Duration) *DCMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 || 10*time.Second < sp {
@@ -238,6 +260,9 @@ func (m *DCMotor) StopAction() (string, error) {
 // issued to the DCMotor.
 func (m *DCMotor) SetStopAction(action string) *DCMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, action)
+		}
 		return m
 	}
 	ok := false
@@ -275,12 +300,19 @@ func (m *DCMotor) TimeSetpoint() (time.Duration, error) {
 // SetTimeSetpoint sets the time setpoint value for the DCMotor.
 func (m *DCMotor) SetTimeSetpoint(sp time.Duration) *DCMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, timeSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, timeSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, timeSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
@@ -5,6 +5,7 @@
 package ev3dev
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -13,6 +14,20 @@ import (
 	"time"
 )
 
+// Sentinel errors matched by errors returned by the package
+// when inspected with errors.Is.
+var (
+	// ErrDeviceDisconnected is matched by an AttrError
+	// when the device is no longer present.
+	ErrDeviceDisconnected = errors.New("ev3dev: device disconnected")
+
+	// ErrInvalidValue is matched by errors returned when
+	// an invalid or out of range value is used for a
+	// device attribute, including errors that satisfy
+	// ValidValuer, ValidRanger or ValidDurationRanger.
+	ErrInvalidValue = errors.New("ev3dev: invalid value")
+)
+
 // ValidValuer is an error caused by an invalid discrete value.
 type ValidValuer interface {
 	// Values returns the invalid value
@@ -101,6 +116,8 @@ func (e invalidValueError) Values() (value string, valid []string) {
 	return e.value, e.valid
 }
 
+func (e invalidValueError) Is(target error) bool { return target == ErrInvalidValue }
+
 type valueOutOfRangeError struct {
 	dev      Device
 	attr     string
@@ -161,6 +178,8 @@ func (e valueOutOfRangeError) Range() (value, min, max int) {
 	return e.value, e.min, e.max
 }
 
+func (e valueOutOfRangeError) Is(target error) bool { return target == ErrInvalidValue }
+
 type idError struct {
 	dev  Device
 	attr string
@@ -217,6 +236,8 @@ func (e idError) Range() (value, min, max int) {
 	return e.id, 0, int(^uint(0) >> 1)
 }
 
+func (e idError) Is(target error) bool { return target == ErrInvalidValue }
+
 type negativeDurationError struct {
 	dev      Device
 	attr     string
@@ -274,6 +295,8 @@ func (e negativeDurationError) DurationRange() (value, min, max time.Duration) {
 	return e.duration, 0, math.MaxInt64
 }
 
+func (e negativeDurationError) Is(target error) bool { return target == ErrInvalidValue }
+
 type durationOutOfRangeError struct {
 	dev      Device
 	attr     string
@@ -334,34 +357,54 @@ func (e durationOutOfRangeError) DurationRange() (value, min, max time.Duration)
 	return e.duration, e.min, e.max
 }
 
-type attrOpError struct {
-	dev  Device
-	attr string
-	data string
-	op   string
-	err  error
+func (e durationOutOfRangeError) Is(target error) bool { return target == ErrInvalidValue }
+
+// AttrError is an error returned when an operation on a device attribute
+// fails. The underlying error is returned by Unwrap. An AttrError matches
+// ErrDeviceDisconnected with errors.Is when the underlying error indicates
+// that the device attribute no longer exists or the device is not available,
+// ENOENT or ENODEV.
+type AttrError struct {
+	// Device is the device holding the attribute.
+	Device Device
+
+	// Attr is the name of the attribute.
+	Attr string
+
+	// Data is the data being written, or
+	// the data read before the failure.
+	Data string
+
+	// Op is the failed operation,
+	// "read", "set" or "watch".
+	Op string
+
+	// Err is the underlying error.
+	Err error
 
 	stack
 }
 
-func newAttrOpError(dev Device, attr, data, op string, err error) attrOpError {
-	return attrOpError{
-		dev:   dev,
-		attr:  attr,
-		data:  data,
-		op:    op,
-		err:   err,
-		stack: callers(),
+func newAttrOpError(dev Device, attr, data, op string, err error) *AttrError {
+	return &AttrError{
+		Device: dev,
+		Attr:   attr,
+		Data:   data,
+		Op:     op,
+		Err:    err,
+		stack:  callers(),
 	}
 }
 
-func (e attrOpError) Error() string {
+func (e *AttrError) Error() string {
 	return fmt.Sprintf("ev3dev: failed to %s %s %s attribute %s: %v at %s",
-		e.op, e.dev, e.attr, filepath.Join(e.dev.Path(), e.dev.String(), e.attr), e.err, e.caller(0))
+		e.Op, e.Device, e.Attr, filepath.Join(e.Device.Path(), e.Device.String(), e.Attr), e.Err, e.caller(0))
 }
 
-func (e attrOpError) Format(fs fmt.State, c rune) {
-	type naked attrOpError
+// Format satisfies the fmt.Formatter interface. The %+v verb
+// prints the error followed by the call stack at its creation.
+func (e *AttrError) Format(fs fmt.State, c rune) {
+	type naked AttrError
 	switch c {
 	case 'v':
 		switch {
@@ -370,8 +413,8 @@ func (e attrOpError) Format(fs fmt.State, c rune) {
 			e.stack.writeTo(fs)
 			return
 		case fs.Flag('#'):
-			n := fmt.Sprintf("%#v", naked(e))
-			fmt.Fprintf(fs, "%T%s", e, n[len("ev3dev.naked"):])
+			n := fmt.Sprintf("%#v", naked(*e))
+			fmt.Fprintf(fs, "&%T%s", *e, n[len("ev3dev.naked"):])
 			return
 		}
 		fallthrough
@@ -380,12 +423,21 @@ func (e attrOpError) Format(fs fmt.State, c rune) {
 	case 'q':
 		fmt.Fprintf(fs, "%q", e.Error())
 	default:
-		fmt.Fprintf(fs, "%"+string(c), naked(e))
+		fmt.Fprintf(fs, "%"+string(c), naked(*e))
 	}
 }
 
-func (e attrOpError) Cause() error  { return e.err }
-func (e attrOpError) Unwrap() error { return e.err }
+// Cause returns the underlying error.
+func (e *AttrError) Cause() error { return e.Err }
+
+// Unwrap returns the underlying error.
+func (e *AttrError) Unwrap() error { return e.Err }
+
+// Is returns whether target is ErrDeviceDisconnected
+// and the underlying error is ENOENT or ENODEV.
+func (e *AttrError) Is(target error) bool {
+	return target == ErrDeviceDisconnected && isDisconnected(e.Err)
+}
 
 type parseError struct {
 	dev  Device
@@ -482,3 +534,69 @@ func cause(err error) error {
 	}
 	return err
 }
+
+// DeviceError is an error from an operation on a device that is held by
+// Errors.
+type DeviceError struct {
+	// Device is the device on which the
+	// operation failed. It is nil if the
+	// failure was not associated with a
+	// device.
+	Device Device
+
+	// Err is the error returned by
+	// the operation.
+	Err error
+}
+
+func (e DeviceError) Error() string { return e.Err.Error() }
+func (e DeviceError) Cause() error  { return e.Err }
+func (e DeviceError) Unwrap() error { return e.Err }
+
+// Errors is a collection of errors from an operation on multiple devices.
+// With go1.13 and later, errors.Is and errors.As match an Errors against
+// each of its elements.
+type Errors []DeviceError
+
+func (e Errors) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	if len(e) == 0 {
+		return "<empty>"
+	}
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err.Err
+	}
+	return fmt.Sprintf("ev3dev: multiple errors: %q", errs)
+}
+
+// Unwrap returns the errors held by e.
+func (e Errors) Unwrap() []error {
+	if len(e) == 0 {
+		return nil
+	}
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Err returns nil if e is empty, the error held by the single DeviceError
+// in e if it has length one, or e otherwise. A single failure is returned
+// unwrapped, without its device attribution.
+func (e Errors) Err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0].Err
+	default:
+		return e
+	}
+}
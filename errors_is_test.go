@@ -0,0 +1,131 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build go1.13
+
+package ev3dev
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestErrorsIs(t *testing.T) {
+	notExist := &os.PathError{Op: "open", Path: "attr", Err: syscall.ENOENT}
+	noDev := &os.PathError{Op: "write", Path: "attr", Err: syscall.ENODEV}
+	perm := &os.PathError{Op: "open", Path: "attr", Err: syscall.EACCES}
+
+	tests := []struct {
+		err              error
+		wantDisconnected bool
+		wantInvalid      bool
+	}{
+		{err: newInvalidValueError(mockDevice{}, "attr", "", "invalid", []string{"valid"}), wantInvalid: true},
+		{err: newValueOutOfRangeError(mockDevice{}, "attr", 0, 1, 2), wantInvalid: true},
+		{err: newIDErrorFor(mockDevice{}, -1), wantInvalid: true},
+		{err: newNegativeDurationError(mockDevice{}, "attr", -1), wantInvalid: true},
+		{err: newDurationOutOfRangeError(mockDevice{}, "attr", 0, 1, 2), wantInvalid: true},
+		{err: newAttrOpError(mockDevice{}, "attr", "", "read", notExist), wantDisconnected: true},
+		{err: newAttrOpError(mockDevice{}, "attr", "data", "set", noDev), wantDisconnected: true},
+		{err: newAttrOpError(mockDevice{}, "attr", "data", "set", perm)},
+		{err: fmt.Errorf("wrapped: %w", newAttrOpError(mockDevice{}, "attr", "", "read", notExist)), wantDisconnected: true},
+		{err: newParseError(mockDevice{}, "attr", syntaxError("bad"))},
+	}
+	for i, test := range tests {
+		if got := errors.Is(test.err, ErrDeviceDisconnected); got != test.wantDisconnected {
+			t.Errorf("unexpected disconnected result for test %d %v: got:%t want:%t", i, test.err, got, test.wantDisconnected)
+		}
+		if got := errors.Is(test.err, ErrInvalidValue); got != test.wantInvalid {
+			t.Errorf("unexpected invalid value result for test %d %v: got:%t want:%t", i, test.err, got, test.wantInvalid)
+		}
+	}
+}
+
+func TestAttrErrorAs(t *testing.T) {
+	motor := filepath.Join(TachoMotorPath, "motor0")
+	root, done := fakeRoot(t, map[string]string{
+		filepath.Join(motor, address):     "ev3-ports:outA\n",
+		filepath.Join(motor, driverName):  "lego-ev3-l-motor\n",
+		filepath.Join(motor, countPerRot): "360\n",
+		filepath.Join(motor, maxSpeed):    "1050\n",
+		filepath.Join(motor, commands):    "run-forever stop reset\n",
+		filepath.Join(motor, stopActions): "coast brake hold\n",
+	})
+	defer done()
+
+	m, err := TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Disconnect the motor.
+	err = os.RemoveAll(filepath.Join(root, motor))
+	if err != nil {
+		t.Fatalf("failed to remove motor: %v", err)
+	}
+
+	_, err = m.Position()
+	var ae *AttrError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected *AttrError: got:%T", err)
+	}
+	if ae.Device != m || ae.Attr != position || ae.Op != "read" {
+		t.Errorf("unexpected attribute error fields: device:%v attr:%q op:%q", ae.Device, ae.Attr, ae.Op)
+	}
+	if !errors.Is(err, ErrDeviceDisconnected) {
+		t.Errorf("expected disconnected device error: %v", err)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not exist error: %v", err)
+	}
+
+	err = m.Command("jump").Err()
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("expected invalid value error: %v", err)
+	}
+}
+
+func TestErrorsCollection(t *testing.T) {
+	notExist := &os.PathError{Op: "open", Path: "attr", Err: syscall.ENOENT}
+	left := &TachoMotor{id: 0}
+	right := &TachoMotor{id: 1}
+
+	var errs Errors
+	if errs.Err() != nil {
+		t.Errorf("unexpected error for empty Errors: %v", errs.Err())
+	}
+	attrErr := newAttrOpError(left, position, "", "read", notExist)
+	errs = append(errs, DeviceError{Device: left, Err: attrErr})
+	if err := errs.Err(); err != attrErr {
+		t.Errorf("unexpected error for single error: got:%v want:%v", err, attrErr)
+	}
+	errs = append(errs, DeviceError{Device: right, Err: newInvalidValueError(right, command, "", "jump", []string{"stop"})})
+	err := fmt.Errorf("wrapped: %w", errs.Err())
+
+	if !errors.Is(err, ErrDeviceDisconnected) {
+		t.Error("expected disconnected device error to match")
+	}
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Error("expected invalid value error to match")
+	}
+	var ae *AttrError
+	if !errors.As(err, &ae) || ae.Device != left {
+		t.Errorf("unexpected attribute error: %v", ae)
+	}
+	var de DeviceError
+	if !errors.As(err, &de) || de.Device != left {
+		t.Errorf("unexpected device error attribution: %v", de.Device)
+	}
+	if got := len(errs.Unwrap()); got != 2 {
+		t.Errorf("unexpected number of unwrapped errors: got:%d want:2", got)
+	}
+	if !strings.HasPrefix(errs.Error(), "ev3dev: multiple errors: ") {
+		t.Errorf("unexpected error string: %q", errs.Error())
+	}
+}
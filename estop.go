@@ -0,0 +1,201 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// EmergencyStop is an emergency stop handler. When an EmergencyStop is
+// triggered, every motor handle held in the package's device registry is
+// stopped, the red LEDs are turned on and the green LEDs turned off, and
+// the context returned by the Context method is cancelled.
+//
+// An EmergencyStop is triggered at most once.
+type EmergencyStop struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	signals chan os.Signal
+	buttons *ButtonWaiter
+	closed  chan struct{}
+	closing sync.Once
+
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// NewEmergencyStop returns a new EmergencyStop that will be triggered by
+// SIGINT or SIGTERM and by a call to its Trigger method. If back is true,
+// the EmergencyStop is also triggered by a press of the Back button.
+// The context returned by the EmergencyStop's Context method is derived
+// from ctx.
+func NewEmergencyStop(ctx context.Context, back bool) (*EmergencyStop, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	e := &EmergencyStop{
+		ctx:     ctx,
+		cancel:  cancel,
+		signals: make(chan os.Signal, 1),
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if back {
+		var err error
+		e.buttons, err = NewButtonWaiter()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		go func() {
+			for ev := range e.buttons.Events {
+				if ev.Err == nil && ev.Button == Back && ev.Value == 1 {
+					e.Trigger()
+				}
+			}
+		}()
+	}
+
+	signal.Notify(e.signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-e.signals:
+			e.Trigger()
+		case <-e.closed:
+		}
+	}()
+
+	return e, nil
+}
+
+// Context returns the context that is cancelled when the EmergencyStop
+// is triggered.
+func (e *EmergencyStop) Context() context.Context {
+	return e.ctx
+}
+
+// Done returns a channel that is closed when the EmergencyStop has been
+// triggered and all stop actions have been performed.
+func (e *EmergencyStop) Done() <-chan struct{} {
+	return e.done
+}
+
+// Trigger triggers the EmergencyStop. Only the first call to Trigger has
+// any effect. Trigger returns when all stop actions have been performed.
+func (e *EmergencyStop) Trigger() {
+	e.once.Do(func() {
+		signal.Stop(e.signals)
+		errs := append(stopAll(), ledsRed()...)
+		e.err = errs.Err()
+		e.cancel()
+		close(e.done)
+	})
+}
+
+// Err returns the errors that occurred when stopping the devices after
+// the EmergencyStop was triggered. If more than one device failed, the
+// returned error is an Errors.
+func (e *EmergencyStop) Err() error {
+	select {
+	case <-e.done:
+		return e.err
+	default:
+		return nil
+	}
+}
+
+// Close stops the EmergencyStop handling signals and button events.
+// Close does not trigger the EmergencyStop, but does cancel its
+// context.
+func (e *EmergencyStop) Close() error {
+	e.closing.Do(func() {
+		signal.Stop(e.signals)
+		close(e.closed)
+		e.cancel()
+		if e.buttons != nil {
+			// The ButtonWaiter does not close until the
+			// next button event is read, so do not wait
+			// for it. The button event goroutine will
+			// drain the Events channel until it is closed.
+			go e.buttons.Close()
+		}
+	})
+	return nil
+}
+
+// StopAll stops every motor handle held in the package's device registry,
+// ignoring any sticky error state held by the handles. Tacho-motors,
+// linear actuators and dc-motors are sent "stop" and servo-motors are sent
+// "float". StopAll attempts to stop all motors. If more than one motor
+// fails to stop, the returned error is an Errors.
+func StopAll() error {
+	return stopAll().Err()
+}
+
+func stopAll() Errors {
+	resLock.Lock()
+	var motors []Device
+	for _, d := range resources[OutputClass] {
+		motors = append(motors, d)
+	}
+	resLock.Unlock()
+
+	var errs Errors
+	for _, d := range motors {
+		var comm string
+		switch d.(type) {
+		case *TachoMotor, *LinearActuator, *DCMotor:
+			comm = "stop"
+		case *ServoMotor:
+			comm = "float"
+		default:
+			continue
+		}
+		err := writeAttributeOf(d, command, comm)
+		if err != nil {
+			errs = append(errs, DeviceError{Device: d, Err: err})
+		}
+	}
+	return errs
+}
+
+// ledsRed turns on all red LEDs and turns off all green LEDs. LEDs are
+// not Devices, so their failures are not attributed.
+func ledsRed() Errors {
+	var l LED
+	names, err := devicesIn(l.Path())
+	if err != nil {
+		return Errors{{Err: err}}
+	}
+	var errs Errors
+	for _, n := range names {
+		l := &LED{Name: LEDName(n)}
+		switch {
+		case strings.Contains(n, ":red:"):
+			max, err := l.MaxBrightness()
+			if err != nil {
+				errs = append(errs, DeviceError{Err: err})
+				continue
+			}
+			err = setAttributeOf(ledDevice{l}, brightness, strconv.Itoa(max))
+			if err != nil {
+				errs = append(errs, DeviceError{Err: err})
+			}
+		case strings.Contains(n, ":green:"):
+			err := setAttributeOf(ledDevice{l}, brightness, "0")
+			if err != nil {
+				errs = append(errs, DeviceError{Err: err})
+			}
+		}
+	}
+	return errs
+}
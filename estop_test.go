@@ -0,0 +1,130 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestEmergencyStop(t *testing.T) {
+	tacho := filepath.Join(TachoMotorPath, "motor0", command)
+	dc := filepath.Join(DCMotorPath, "motor1", command)
+	servo := filepath.Join(ServoMotorPath, "motor2", command)
+	red := filepath.Join(LEDPath, "led0:red:brick-status")
+	green := filepath.Join(LEDPath, "led0:green:brick-status")
+	root, done := fakeRoot(t, map[string]string{
+		tacho:                               "",
+		dc:                                  "",
+		servo:                               "",
+		filepath.Join(red, brightness):      "0\n",
+		filepath.Join(red, maxBrightness):   "255\n",
+		filepath.Join(green, brightness):    "255\n",
+		filepath.Join(green, maxBrightness): "255\n",
+	})
+	defer done()
+
+	resLock.Lock()
+	savedResources := resources
+	resources = map[string]map[string]Device{
+		InputClass: make(map[string]Device),
+		OutputClass: map[string]Device{
+			"ev3-ports:outA": &TachoMotor{id: 0},
+			"ev3-ports:outB": &DCMotor{id: 1},
+			"ev3-ports:outC": &ServoMotor{id: 2},
+		},
+		PortClass: make(map[string]Device),
+	}
+	resLock.Unlock()
+	defer func() {
+		resLock.Lock()
+		resources = savedResources
+		resLock.Unlock()
+	}()
+
+	e, err := NewEmergencyStop(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer e.Close()
+
+	select {
+	case <-e.Done():
+		t.Fatal("unexpected done before trigger")
+	default:
+	}
+	e.Trigger()
+	select {
+	case <-e.Done():
+	default:
+		t.Error("expected done after trigger")
+	}
+	if e.Context().Err() != context.Canceled {
+		t.Errorf("unexpected context error: got:%v want:%v", e.Context().Err(), context.Canceled)
+	}
+	if e.Err() != nil {
+		t.Errorf("unexpected error: %v", e.Err())
+	}
+
+	for _, test := range []struct {
+		path, want string
+	}{
+		{path: tacho, want: "stop"},
+		{path: dc, want: "stop"},
+		{path: servo, want: "float"},
+		{path: filepath.Join(red, brightness), want: "255"},
+		{path: filepath.Join(green, brightness), want: "0"},
+	} {
+		got, err := ioutil.ReadFile(filepath.Join(root, test.path))
+		if err != nil {
+			t.Fatalf("unexpected error reading %s: %v", test.path, err)
+		}
+		if string(chomp(got)) != test.want {
+			t.Errorf("unexpected value for %s: got:%q want:%q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestStopAllErrors(t *testing.T) {
+	// Only motor0 has a command attribute.
+	_, done := fakeRoot(t, map[string]string{
+		filepath.Join(TachoMotorPath, "motor0", command): "",
+	})
+	defer done()
+
+	failing := []Device{&TachoMotor{id: 1}, &ServoMotor{id: 2}}
+	resLock.Lock()
+	savedResources := resources
+	resources = map[string]map[string]Device{
+		InputClass: make(map[string]Device),
+		OutputClass: map[string]Device{
+			"ev3-ports:outA": &TachoMotor{id: 0},
+			"ev3-ports:outB": failing[0],
+			"ev3-ports:outC": failing[1],
+		},
+		PortClass: make(map[string]Device),
+	}
+	resLock.Unlock()
+	defer func() {
+		resLock.Lock()
+		resources = savedResources
+		resLock.Unlock()
+	}()
+
+	err := StopAll()
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("unexpected error type: got:%T want:%T", err, Errors(nil))
+	}
+	got := make(map[Device]bool)
+	for _, e := range errs {
+		got[e.Device] = true
+	}
+	if len(errs) != len(failing) || !got[failing[0]] || !got[failing[1]] {
+		t.Errorf("unexpected errors: got:%v want failures for %v", errs, failing)
+	}
+}
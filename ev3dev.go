@@ -53,9 +53,28 @@ const (
 )
 
 // prefix is the filesystem root prefix.
-// Currently it is used only for testing.
 var prefix = ""
 
+// SetRoot sets the filesystem root under which the package finds device
+// files. The default root is the empty string, referring to the system root.
+//
+// The root is prepended to the device class paths, LEDPath, LegoPortPath,
+// SensorPath, TachoMotorPath, ServoMotorPath, DCMotorPath and PowerSupplyPath,
+// and to ButtonPath, so every device type, Inventory, HotplugWatcher and the
+// button pollers and waiters use files under the root. This allows the package
+// to be used with a generated or simulated device tree. Paths given explicitly
+// by the user, such as the path passed to NewSpeaker, are not altered.
+//
+// SetRoot is not safe for concurrent use with other functions in the package,
+// and should be called before any device handles are obtained. Handles obtained
+// before a call to SetRoot refer to the new root after the call, and the device
+// registry is not reset.
+func SetRoot(root string) { prefix = root }
+
+// Root returns the filesystem root set by SetRoot.
+func Root() string { return prefix }
+
+// The following paths are relative to the filesystem root set by SetRoot.
 const (
 	// LEDPath is the path to the ev3 LED file system.
 	LEDPath = "/sys/class/leds"
@@ -235,6 +254,11 @@ type StaterDevice interface {
 var canPoll = true
 
 func motorState(d Device, f *os.File) (MotorState, error) {
+	if f == nil {
+		// Device I/O is recorded or replayed.
+		_, data, _, err := attributeOf(d, state)
+		return stateFrom(d, data, state, err)
+	}
 	var b [4096]byte
 	n, err := f.ReadAt(b[:], 0)
 	if n == len(b) && err == nil {
@@ -257,6 +281,21 @@ func stateIsOK(stat, mask, want, not MotorState, any bool) bool {
 	return (stat&mask)^not == want|not
 }
 
+// StateCondition is a motor state condition for a device. The Mask, Want,
+// Not and Any fields have the same semantics as the mask, want, not and any
+// parameters of Wait.
+type StateCondition struct {
+	Device StaterDevice
+
+	Mask, Want, Not MotorState
+	Any             bool
+}
+
+// isOK returns whether stat satisfies the condition.
+func (c StateCondition) isOK(stat MotorState) bool {
+	return stateIsOK(stat, c.Mask, c.Want, c.Not, c.Any)
+}
+
 // DriverMismatch errors are returned when a device is found that
 // does not match the requested driver.
 type DriverMismatch struct {
@@ -349,7 +388,7 @@ func IsConnected(d Device) (ok bool, err error) {
 
 // AddressOf returns the port address of the Device.
 func AddressOf(d Device) (string, error) {
-	b, err := ioutil.ReadFile(fmt.Sprintf(d.Path()+"/%s/"+address, d))
+	b, err := readFile(fmt.Sprintf(d.Path()+"/%s/"+address, d))
 	if err != nil {
 		return "", fmt.Errorf(wrapped("ev3dev: failed to read %s address: %w"), d.Type(), err)
 	}
@@ -358,7 +397,7 @@ func AddressOf(d Device) (string, error) {
 
 // DriverFor returns the driver name for the Device.
 func DriverFor(d Device) (string, error) {
-	b, err := ioutil.ReadFile(fmt.Sprintf(d.Path()+"/%s/"+driverName, d))
+	b, err := readFile(fmt.Sprintf(d.Path()+"/%s/"+driverName, d))
 	if err != nil {
 		return "", fmt.Errorf(wrapped("ev3dev: failed to read %s driver name: %w"), d.Type(), err)
 	}
@@ -440,30 +479,50 @@ func deviceIDFor(port, driver string, d Device, after int) (int, error) {
 
 func probeAttributeFor(d Device, name, attr string) ([]byte, error) {
 	path := filepath.Join(d.Path(), name, attr)
-	b, err := ioutil.ReadFile(path)
+	b, err := readFile(path)
 	if err != nil {
 		return nil, newAttrOpError(d, attr, string(b), "read", err)
 	}
 	return b, nil
 }
 
+// These are the device registry classes.
+const (
+	// InputClass is the registry class for devices
+	// connected to input ports, lego-sensors.
+	InputClass = "in"
+
+	// OutputClass is the registry class for devices
+	// connected to output ports, tacho-motors, servo-motors,
+	// dc-motors and linear actuators.
+	OutputClass = "out"
+
+	// PortClass is the registry class for lego-ports.
+	PortClass = "port"
+)
+
 var (
 	resLock   sync.Mutex
 	resources = map[string]map[string]Device{
-		"in":   make(map[string]Device),
-		"out":  make(map[string]Device),
-		"port": make(map[string]Device),
+		InputClass:  make(map[string]Device),
+		OutputClass: make(map[string]Device),
+		PortClass:   make(map[string]Device),
 	}
 )
 
-func inUse(d Device, address []byte) bool {
-	typ := d.Type()
+// registryClass returns the registry class for the given device type.
+func registryClass(typ string) string {
 	switch typ {
-	case "linear", "motor":
-		typ = "out"
-	case "sensor":
-		typ = "in"
+	case linearPrefix, motorPrefix:
+		return OutputClass
+	case sensorPrefix:
+		return InputClass
 	}
+	return typ
+}
+
+func inUse(d Device, address []byte) bool {
+	typ := registryClass(d.Type())
 	id := d.String()
 
 	resLock.Lock()
@@ -486,7 +545,86 @@ func inUse(d Device, address []byte) bool {
 	return true
 }
 
+// Claim is a record of a device handle held by the device registry.
+type Claim struct {
+	// Class is the registry class of the
+	// claim, one of InputClass, OutputClass
+	// or PortClass.
+	Class string
+
+	// Address is the port address
+	// the handle was claimed for.
+	Address string
+
+	// Device is the claiming handle.
+	Device Device
+}
+
+// Claims returns the device handles held by the device registry, sorted
+// by class and then address. Claims does not check whether the claimed
+// devices are still connected; a stale claim is replaced when a new handle
+// for the address is requested.
+func Claims() []Claim {
+	resLock.Lock()
+	defer resLock.Unlock()
+
+	var claims []Claim
+	for class, devices := range resources {
+		for addr, d := range devices {
+			claims = append(claims, Claim{Class: class, Address: addr, Device: d})
+		}
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].Class != claims[j].Class {
+			return claims[i].Class < claims[j].Class
+		}
+		return claims[i].Address < claims[j].Address
+	})
+	return claims
+}
+
+// HandleFor returns the device handle held by the device registry for the
+// given class and port address. If no handle is held, HandleFor returns nil.
+func HandleFor(class, address string) Device {
+	resLock.Lock()
+	defer resLock.Unlock()
+	return resources[class][address]
+}
+
+// Release releases the claim held by the device registry for the device
+// handle d, allowing a new handle, possibly of a different type, to be
+// obtained for the physical device. The released handle must not be used
+// after a new handle has been obtained for the device. Any attribute files
+// held open by d are closed. Release returns an error if d is not held by
+// the registry.
+func Release(d Device) error {
+	resLock.Lock()
+	defer resLock.Unlock()
+
+	for addr, attached := range resources[registryClass(d.Type())] {
+		if attached == d {
+			delete(resources[registryClass(d.Type())], addr)
+			return fileCacheOf(d).invalidate()
+		}
+	}
+	return fmt.Errorf("ev3dev: %s not held by registry", d)
+}
+
 func devicesIn(path string) ([]string, error) {
+	if sess != nil {
+		names, err := sess.do(listOp, path, "", func() (string, error) {
+			names, err := readDirnames(path)
+			return strings.Join(names, "\n"), err
+		})
+		if names == "" {
+			return nil, err
+		}
+		return strings.Split(names, "\n"), err
+	}
+	return readDirnames(path)
+}
+
+func readDirnames(path string) ([]string, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -528,15 +666,57 @@ func attributeOf(d Device, attr string) (dev Device, data string, _attr string,
 		return d, "", "", err
 	}
 	path := filepath.Join(d.Path(), d.String(), attr)
-	b, err := ioutil.ReadFile(path)
+	b, err := readAttr(d, path, attr)
+	if err != nil && rebind(d, err) {
+		path = filepath.Join(d.Path(), d.String(), attr)
+		b, err = readAttr(d, path, attr)
+	}
 	if err != nil {
 		return d, "", "", newAttrOpError(d, attr, string(b), "read", err)
 	}
 	return d, string(chomp(b)), attr, nil
 }
 
+// ReadAttrs returns the values of the named attributes of d, read in order
+// as closely together as possible, and the time at which the reads began.
+// If any attribute cannot be read, ReadAttrs returns the first error and
+// no values. Attributes held open by a handle with KeepFilesOpen enabled
+// are read with a single pread(2) call each.
+func ReadAttrs(d Device, attrs ...string) (values []string, t time.Time, err error) {
+	err = d.Err()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	values = make([]string, len(attrs))
+	t = clock.Now()
+	for i, attr := range attrs {
+		_, values[i], _, err = attributeOf(d, attr)
+		if err != nil {
+			return nil, t, err
+		}
+	}
+	return values, t, nil
+}
+
+// readAttr reads the attribute file at path, using the file cache of d
+// if it holds attr open.
+func readAttr(d Device, path, attr string) ([]byte, error) {
+	if sess != nil {
+		return sessionRead(path, func() ([]byte, error) { return readAttrFile(d, path, attr) })
+	}
+	return readAttrFile(d, path, attr)
+}
+
+func readAttrFile(d Device, path, attr string) ([]byte, error) {
+	b, ok, err := fileCacheOf(d).read(path, attr)
+	if ok {
+		return b, err
+	}
+	return ioutil.ReadFile(path)
+}
+
 func chomp(b []byte) []byte {
-	if b[len(b)-1] == '\n' {
+	if len(b) != 0 && b[len(b)-1] == '\n' {
 		return b[:len(b)-1]
 	}
 	return b
@@ -625,11 +805,67 @@ func ueventFrom(d Device, data, attr string, err error) (map[string]string, erro
 	return uevent, nil
 }
 
+// setAttributeOf writes data to attr of d, or stages the write if d is
+// staging a transaction.
 func setAttributeOf(d Device, attr, data string) error {
+	return setLoggedAttributeOf(d, "write", LogDebug, attr, data)
+}
+
+// setLoggedAttributeOf is setAttributeOf, logging the write with msg at
+// level when it is made rather than as an attribute write.
+func setLoggedAttributeOf(d Device, msg string, level LogLevel, attr, data string) error {
+	if stage(d, stagedWrite{msg: msg, level: level, attr: attr, data: data}) {
+		return nil
+	}
+	return writeLoggedAttributeOf(d, msg, level, attr, data)
+}
+
+// writeAttributeOf writes data to attr of d, retrying once if d is rebound.
+// Writes made by writeAttributeOf are never staged.
+func writeAttributeOf(d Device, attr, data string) error {
+	return writeLoggedAttributeOf(d, "write", LogDebug, attr, data)
+}
+
+// writeLoggedAttributeOf is writeAttributeOf, logging the write with msg
+// at level rather than as an attribute write.
+func writeLoggedAttributeOf(d Device, msg string, level LogLevel, attr, data string) error {
 	path := filepath.Join(d.Path(), d.String(), attr)
-	err := ioutil.WriteFile(path, []byte(data), 0)
+	err := writeAttr(d, path, attr, data)
+	if err != nil && rebind(d, err) {
+		path = filepath.Join(d.Path(), d.String(), attr)
+		err = writeAttr(d, path, attr, data)
+	}
 	if err != nil {
-		return newAttrOpError(d, attr, data, "set", err)
+		err = newAttrOpError(d, attr, data, "set", err)
+		if logger != nil {
+			logOp(d, msg, level, attr, data, err)
+		}
+		return err
+	}
+	if logger != nil {
+		logOp(d, msg, level, attr, data, nil)
+	}
+	if rd, ok := d.(resilientDevice); ok {
+		if r := rd.resilience(); r != nil {
+			r.record(attr, data)
+		}
 	}
 	return nil
 }
+
+// writeAttr writes data to the attribute file at path, using the file
+// cache of d if it holds attr open.
+func writeAttr(d Device, path, attr, data string) error {
+	if sess != nil {
+		return sessionWrite(path, data, func() error { return writeAttrFile(d, path, attr, data) })
+	}
+	return writeAttrFile(d, path, attr, data)
+}
+
+func writeAttrFile(d Device, path, attr, data string) error {
+	ok, err := fileCacheOf(d).write(path, attr, data)
+	if ok {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(data), 0)
+}
@@ -0,0 +1,143 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var ueventEventTests = []struct {
+	msg    string
+	want   HotplugEvent
+	wantOK bool
+}{
+	{
+		msg: "add@/devices/platform/ev3-ports/ev3-ports:outA/lego-port/port4/ev3-ports:outA:lego-ev3-l-motor/tacho-motor/motor0\x00" +
+			"ACTION=add\x00DEVPATH=/devices/platform/ev3-ports/ev3-ports:outA/lego-port/port4/ev3-ports:outA:lego-ev3-l-motor/tacho-motor/motor0\x00" +
+			"SUBSYSTEM=tacho-motor\x00LEGO_DRIVER_NAME=lego-ev3-l-motor\x00LEGO_ADDRESS=ev3-ports:outA\x00SEQNUM=1234\x00",
+		want: HotplugEvent{
+			Action: DeviceAdded, Class: TachoMotorClass, Name: "motor0",
+			Address: "ev3-ports:outA", Driver: "lego-ev3-l-motor",
+		},
+		wantOK: true,
+	},
+	{
+		msg: "remove@/devices/platform/ev3-ports/ev3-ports:in1/lego-sensor/sensor3\x00" +
+			"ACTION=remove\x00DEVPATH=/devices/platform/ev3-ports/ev3-ports:in1/lego-sensor/sensor3\x00" +
+			"SUBSYSTEM=lego-sensor\x00LEGO_DRIVER_NAME=lego-ev3-touch\x00LEGO_ADDRESS=ev3-ports:in1\x00",
+		want: HotplugEvent{
+			Action: DeviceRemoved, Class: SensorClass, Name: "sensor3",
+			Address: "ev3-ports:in1", Driver: "lego-ev3-touch",
+		},
+		wantOK: true,
+	},
+	{
+		msg:    "change@/devices/platform/ev3-ports/ev3-ports:in1/lego-sensor/sensor3\x00ACTION=change\x00SUBSYSTEM=lego-sensor\x00",
+		wantOK: false,
+	},
+	{
+		msg:    "add@/devices/virtual/net/lo\x00ACTION=add\x00DEVPATH=/devices/virtual/net/lo\x00SUBSYSTEM=net\x00",
+		wantOK: false,
+	},
+	{
+		msg:    "libudev\x00\xfe\xed\xca\xfe",
+		wantOK: false,
+	},
+}
+
+func TestUeventEvent(t *testing.T) {
+	for _, test := range ueventEventTests {
+		got, ok := ueventEvent([]byte(test.msg))
+		if ok != test.wantOK {
+			t.Errorf("unexpected ok for %q: got:%t want:%t", test.msg, ok, test.wantOK)
+		}
+		if got != test.want {
+			t.Errorf("unexpected event for %q:\ngot: %+v\nwant:%+v", test.msg, got, test.want)
+		}
+	}
+}
+
+func TestPollingHotplugWatcher(t *testing.T) {
+	root, done := fakeRoot(t, map[string]string{
+		TachoMotorPath + "/motor0/address":     "ev3-ports:outA\n",
+		TachoMotorPath + "/motor0/driver_name": "lego-ev3-l-motor\n",
+	})
+	defer done()
+
+	w, err := NewHotplugWatcher(10 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	next := func() HotplugEvent {
+		select {
+		case ev := <-w.Events:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+			return HotplugEvent{}
+		}
+	}
+
+	writeTree(t, root, map[string]string{
+		SensorPath + "/sensor2/address":     "ev3-ports:in1\n",
+		SensorPath + "/sensor2/driver_name": "lego-ev3-touch\n",
+	})
+	got := next()
+	want := HotplugEvent{Action: DeviceAdded, Class: SensorClass, Name: "sensor2", Address: "ev3-ports:in1", Driver: "lego-ev3-touch"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected event:\ngot: %+v\nwant:%+v", got, want)
+	}
+
+	err = os.RemoveAll(filepath.Join(root, TachoMotorPath, "motor0"))
+	if err != nil {
+		t.Fatalf("failed to remove device: %v", err)
+	}
+	got = next()
+	want = HotplugEvent{Action: DeviceRemoved, Class: TachoMotorClass, Name: "motor0", Address: "ev3-ports:outA", Driver: "lego-ev3-l-motor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected event:\ngot: %+v\nwant:%+v", got, want)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Errorf("unexpected error closing watcher: %v", err)
+	}
+	if _, ok := <-w.Events; ok {
+		t.Error("expected closed Events channel")
+	}
+}
+
+func TestHotplugWatcherConcurrentClose(t *testing.T) {
+	_, done := fakeRoot(t, nil)
+	defer done()
+
+	w, err := NewHotplugWatcher(10 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.Close()
+			if err != nil {
+				t.Errorf("unexpected error closing watcher: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, ok := <-w.Events; ok {
+		t.Error("expected closed Events channel")
+	}
+}
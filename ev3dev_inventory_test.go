@@ -0,0 +1,65 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	_, done := fakeRoot(t, map[string]string{
+		LegoPortPath + "/port4/address":                   "ev3-ports:outA\n",
+		LegoPortPath + "/port4/driver_name":               "legoev3-output-port\n",
+		LegoPortPath + "/port4/modes":                     "auto tacho-motor dc-motor\n",
+		SensorPath + "/sensor1/address":                   "ev3-ports:in2\n",
+		SensorPath + "/sensor1/driver_name":               "lego-ev3-touch\n",
+		SensorPath + "/sensor1/modes":                     "TOUCH\n",
+		SensorPath + "/sensor1/commands":                  "\n",
+		TachoMotorPath + "/motor10/address":               "ev3-ports:outB\n",
+		TachoMotorPath + "/motor10/driver_name":           "lego-ev3-l-motor\n",
+		TachoMotorPath + "/motor10/commands":              "run-forever stop reset\n",
+		TachoMotorPath + "/motor2/address":                "ev3-ports:outA\n",
+		TachoMotorPath + "/motor2/driver_name":            "lego-ev3-m-motor\n",
+		TachoMotorPath + "/motor2/commands":               "run-forever stop reset\n",
+		LEDPath + "/led1:red:brick-status/brightness":     "0\n",
+		LEDPath + "/led0:green:brick-status/brightness":   "0\n",
+		PowerSupplyPath + "/lego-ev3-battery/voltage_now": "7500000\n",
+	})
+	defer done()
+
+	got, err := Inventory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []DeviceInfo{
+		{
+			Class: LegoPortClass, Name: "port4", ID: 4,
+			Address: "ev3-ports:outA", Driver: "legoev3-output-port",
+			Modes: []string{"auto", "tacho-motor", "dc-motor"},
+		},
+		{
+			Class: SensorClass, Name: "sensor1", ID: 1,
+			Address: "ev3-ports:in2", Driver: "lego-ev3-touch",
+			Modes: []string{"TOUCH"},
+		},
+		{
+			Class: TachoMotorClass, Name: "motor2", ID: 2,
+			Address: "ev3-ports:outA", Driver: "lego-ev3-m-motor",
+			Commands: []string{"run-forever", "stop", "reset"},
+		},
+		{
+			Class: TachoMotorClass, Name: "motor10", ID: 10,
+			Address: "ev3-ports:outB", Driver: "lego-ev3-l-motor",
+			Commands: []string{"run-forever", "stop", "reset"},
+		},
+		{Class: LEDClass, Name: "led0:green:brick-status", ID: -1},
+		{Class: LEDClass, Name: "led1:red:brick-status", ID: -1},
+		{Class: PowerSupplyClass, Name: "lego-ev3-battery", ID: -1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected inventory:\ngot: %+v\nwant:%+v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	resLock.Lock()
+	saved := resources
+	resources = map[string]map[string]Device{
+		InputClass:  make(map[string]Device),
+		OutputClass: make(map[string]Device),
+		PortClass:   make(map[string]Device),
+	}
+	resLock.Unlock()
+	defer func() {
+		resLock.Lock()
+		resources = saved
+		resLock.Unlock()
+	}()
+
+	motorA := &TachoMotor{id: 0}
+	motorB := &DCMotor{id: 1}
+	sensor1 := &Sensor{id: 2}
+	portA := &LegoPort{id: 3}
+	resources[OutputClass]["ev3-ports:outB"] = motorB
+	resources[OutputClass]["ev3-ports:outA"] = motorA
+	resources[InputClass]["ev3-ports:in1"] = sensor1
+	resources[PortClass]["ev3-ports:outA"] = portA
+
+	want := []Claim{
+		{Class: InputClass, Address: "ev3-ports:in1", Device: sensor1},
+		{Class: OutputClass, Address: "ev3-ports:outA", Device: motorA},
+		{Class: OutputClass, Address: "ev3-ports:outB", Device: motorB},
+		{Class: PortClass, Address: "ev3-ports:outA", Device: portA},
+	}
+	got := Claims()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected claims:\ngot: %v\nwant:%v", got, want)
+	}
+
+	if got := HandleFor(OutputClass, "ev3-ports:outA"); got != motorA {
+		t.Errorf("unexpected handle for %s: got:%v want:%v", "ev3-ports:outA", got, motorA)
+	}
+	if got := HandleFor(PortClass, "ev3-ports:outA"); got != portA {
+		t.Errorf("unexpected handle for %s: got:%v want:%v", "ev3-ports:outA", got, portA)
+	}
+	if got := HandleFor(InputClass, "ev3-ports:in4"); got != nil {
+		t.Errorf("unexpected handle for %s: got:%v want:nil", "ev3-ports:in4", got)
+	}
+
+	err := Release(motorA)
+	if err != nil {
+		t.Errorf("unexpected error releasing %v: %v", motorA, err)
+	}
+	if got := HandleFor(OutputClass, "ev3-ports:outA"); got != nil {
+		t.Errorf("unexpected handle for released %s: got:%v want:nil", "ev3-ports:outA", got)
+	}
+	err = Release(motorA)
+	if err == nil {
+		t.Errorf("expected error releasing %v twice", motorA)
+	}
+	err = Release(&TachoMotor{id: 0})
+	if err == nil {
+		t.Error("expected error releasing unheld handle")
+	}
+	if got := len(Claims()); got != len(want)-1 {
+		t.Errorf("unexpected number of claims after release: got:%d want:%d", got, len(want)-1)
+	}
+}
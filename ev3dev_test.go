@@ -71,7 +71,7 @@ func chomp(b []byte) []byte {
 	return b
 }
 
-func serve(fs *sisyphus.FileSystem, t *testing.T) (unmount func()) {
+func serve(fs *sisyphus.FileSystem, t testing.TB) (unmount func()) {
 	c, err := sisyphus.Serve(ev3dev.Prefix, fs, nil, fuse.AllowNonEmptyMount())
 	if err != nil {
 		t.Fatalf("failed to open server: %v", err)
@@ -0,0 +1,590 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ev3devtest provides a fake ev3dev device tree for testing programs
+// that use the ev3dev package.
+//
+// A Brick is populated with ports, motors, sensors, LEDs and a battery and then
+// either written to a directory with Start or served as a FUSE filesystem with
+// Mount. Both set the ev3dev package's filesystem root so that device handles
+// obtained from the ev3dev package refer to the fake devices. For example:
+//
+//  b := ev3devtest.NewEV3()
+//  m := b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor")
+//  s := b.AddSensor("ev3-ports:in1", "lego-ev3-touch", "TOUCH")
+//  err := b.Start("")
+//  if err != nil {
+//  	t.Fatal(err)
+//  }
+//  defer b.Close()
+//
+//  s.SetValues(1)
+//  // Run code under test that reads the sensor and drives the motor...
+//  if got := m.Attr("command"); got != "run-forever" {
+//  	t.Errorf("unexpected command: %q", got)
+//  }
+//
+// Sensor values and other attributes are scripted with SetAttr and SetValues,
+// and attributes written by the code under test are observed with Attr. When
+// the Brick is mounted, every write is also recorded and reported to hooks
+// registered with OnWrite.
+//
+// Tacho-motors may be given a physical simulation with Simulate, so that
+// their position, speed and state respond to commands as the simulation is
+// advanced with Advance or Run.
+package ev3devtest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ev3go/ev3dev"
+)
+
+// Brick is a fake ev3dev device tree.
+type Brick struct {
+	mu sync.Mutex
+
+	devices []*Device
+	ids     map[string]int
+
+	// motors holds the simulated
+	// tacho-motors of the Brick.
+	motors []*Motor
+
+	// root is the filesystem root of the
+	// started or mounted device tree.
+	root string
+
+	// dir is true when the device tree
+	// is held in regular files.
+	dir bool
+
+	// close releases the resources held
+	// by the started or mounted tree.
+	close func() error
+
+	savedRoot string
+}
+
+// NewBrick returns a new empty Brick.
+func NewBrick() *Brick {
+	return &Brick{ids: make(map[string]int)}
+}
+
+// NewEV3 returns a new Brick populated with the input and output ports, the
+// brick status LEDs and the battery of an EV3.
+func NewEV3() *Brick {
+	b := NewBrick()
+	for _, p := range []string{"in1", "in2", "in3", "in4"} {
+		b.AddPort("ev3-ports:"+p, "legoev3-input-port",
+			"auto", "ev3-analog", "ev3-uart", "nxt-analog", "nxt-color", "nxt-i2c", "other-i2c", "raw")
+	}
+	for _, p := range []string{"outA", "outB", "outC", "outD"} {
+		b.AddPort("ev3-ports:"+p, "legoev3-output-port",
+			"auto", "tacho-motor", "dc-motor", "led", "raw")
+	}
+	for _, n := range []string{"led0:red:brick-status", "led0:green:brick-status", "led1:red:brick-status", "led1:green:brick-status"} {
+		b.AddLED(n)
+	}
+	b.AddBattery("lego-ev3-battery")
+	return b
+}
+
+// Device is a fake device in a Brick.
+type Device struct {
+	// Class is the device class of the
+	// device and Name is its sysfs name.
+	Class string
+	Name  string
+
+	brick *Brick
+
+	attrs map[string]*attribute
+	names []string
+
+	hooks  []func(attr, value string)
+	writes []Write
+}
+
+// Write is a record of a write to a device attribute.
+type Write struct {
+	Attr  string
+	Value string
+}
+
+// attribute is a fake sysfs attribute.
+type attribute struct {
+	dev    *Device
+	name   string
+	access ev3dev.Access
+	value  string
+
+	// valid, if not nil, returns the
+	// valid values for the attribute.
+	valid func() []string
+
+	// apply, if not nil, returns the
+	// stored value after writing v.
+	apply func(old, v string) string
+}
+
+// classPaths holds the sysfs path of each device class.
+var classPaths = map[string]string{
+	ev3dev.LegoPortClass:    ev3dev.LegoPortPath,
+	ev3dev.SensorClass:      ev3dev.SensorPath,
+	ev3dev.TachoMotorClass:  ev3dev.TachoMotorPath,
+	ev3dev.ServoMotorClass:  ev3dev.ServoMotorPath,
+	ev3dev.DCMotorClass:     ev3dev.DCMotorPath,
+	ev3dev.LEDClass:         ev3dev.LEDPath,
+	ev3dev.PowerSupplyClass: ev3dev.PowerSupplyPath,
+}
+
+// add adds a device of the given class to the Brick. If name is empty, the
+// device is named with the prefix and the next id for the class path. The
+// device has every attribute in the class schema, with values taken from
+// defaults or the zero value for the attribute type.
+func (b *Brick) add(class, name, prefix string, defaults map[string]string) *Device {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.close != nil {
+		panic("ev3devtest: device added to started brick")
+	}
+	if name == "" {
+		path := classPaths[class]
+		name = prefix + strconv.Itoa(b.ids[path])
+		b.ids[path]++
+	}
+	d := &Device{Class: class, Name: name, brick: b, attrs: make(map[string]*attribute)}
+	for _, a := range ev3dev.Schema(class) {
+		if a.Type == ev3dev.BinaryAttr && a.Access&ev3dev.Write != 0 {
+			// Direct access is not faked.
+			continue
+		}
+		v, ok := defaults[a.Name]
+		if !ok && (a.Type == ev3dev.IntAttr || a.Type == ev3dev.DurationAttr) {
+			v = "0"
+		}
+		d.attrs[a.Name] = &attribute{dev: d, name: a.Name, access: a.Access, value: v}
+		d.names = append(d.names, a.Name)
+	}
+	b.devices = append(b.devices, d)
+	return d
+}
+
+// uevent returns a uevent attribute value for the given address and driver.
+func uevent(address, driver string) string {
+	return "LEGO_ADDRESS=" + address + "\nLEGO_DRIVER_NAME=" + driver
+}
+
+// AddPort adds a lego-port with the given address, driver and modes. The
+// port's initial mode is the first mode.
+func (b *Brick) AddPort(address, driver string, modes ...string) *Device {
+	var mode string
+	if len(modes) != 0 {
+		mode = modes[0]
+	}
+	d := b.add(ev3dev.LegoPortClass, "", "port", map[string]string{
+		"address":     address,
+		"driver_name": driver,
+		"modes":       strings.Join(modes, " "),
+		"mode":        mode,
+		"status":      mode,
+		"uevent":      uevent(address, driver),
+	})
+	d.restrict("mode", "modes")
+	return d
+}
+
+// AddSensor adds a lego-sensor with the given address, driver and modes. The
+// sensor's initial mode is the first mode and it has a single value of zero.
+func (b *Brick) AddSensor(address, driver string, modes ...string) *Device {
+	var mode string
+	if len(modes) != 0 {
+		mode = modes[0]
+	}
+	d := b.add(ev3dev.SensorClass, "", "sensor", map[string]string{
+		"address":         address,
+		"driver_name":     driver,
+		"bin_data_format": "s32",
+		"modes":           strings.Join(modes, " "),
+		"mode":            mode,
+		"num_values":      "1",
+		"uevent":          uevent(address, driver),
+	})
+	d.restrict("mode", "modes")
+	d.restrict("command", "commands")
+	return d
+}
+
+// tachoCommands are the commands of a fake tacho-motor.
+var tachoCommands = "run-forever run-to-abs-pos run-to-rel-pos run-timed run-direct stop reset"
+
+// AddTachoMotor adds a tacho-motor with the given address and driver.
+func (b *Brick) AddTachoMotor(address, driver string) *Device {
+	maxSpeed := "1050"
+	if driver == "lego-ev3-m-motor" {
+		maxSpeed = "1560"
+	}
+	d := b.add(ev3dev.TachoMotorClass, "", "motor", map[string]string{
+		"address":       address,
+		"driver_name":   driver,
+		"commands":      tachoCommands,
+		"count_per_rot": "360",
+		"max_speed":     maxSpeed,
+		"polarity":      string(ev3dev.Normal),
+		"stop_action":   "coast",
+		"stop_actions":  "coast brake hold",
+		"uevent":        uevent(address, driver),
+	})
+	d.restrict("command", "commands")
+	d.restrict("stop_action", "stop_actions")
+	d.restrictTo("polarity", string(ev3dev.Normal), string(ev3dev.Inversed))
+	return d
+}
+
+// AddServoMotor adds a servo-motor with the given address and driver.
+func (b *Brick) AddServoMotor(address, driver string) *Device {
+	d := b.add(ev3dev.ServoMotorClass, "", "motor", map[string]string{
+		"address":      address,
+		"driver_name":  driver,
+		"max_pulse_sp": "2400",
+		"mid_pulse_sp": "1500",
+		"min_pulse_sp": "600",
+		"polarity":     string(ev3dev.Normal),
+		"uevent":       uevent(address, driver),
+	})
+	d.restrictTo("command", "run", "float")
+	d.restrictTo("polarity", string(ev3dev.Normal), string(ev3dev.Inversed))
+	return d
+}
+
+// AddDCMotor adds a dc-motor with the given address and driver.
+func (b *Brick) AddDCMotor(address, driver string) *Device {
+	d := b.add(ev3dev.DCMotorClass, "", "motor", map[string]string{
+		"address":      address,
+		"driver_name":  driver,
+		"commands":     "run-forever run-timed run-direct stop",
+		"polarity":     string(ev3dev.Normal),
+		"stop_action":  "coast",
+		"stop_actions": "coast brake",
+		"uevent":       uevent(address, driver),
+	})
+	d.restrict("command", "commands")
+	d.restrict("stop_action", "stop_actions")
+	d.restrictTo("polarity", string(ev3dev.Normal), string(ev3dev.Inversed))
+	return d
+}
+
+// AddLED adds an LED with the given name and a maximum brightness of 255.
+func (b *Brick) AddLED(name string) *Device {
+	d := b.add(ev3dev.LEDClass, name, "", map[string]string{
+		"max_brightness": "255",
+		"trigger":        "[none] timer heartbeat default-on",
+	})
+	d.attrs["trigger"].apply = func(old, v string) string {
+		trigs := strings.Fields(old)
+		for i, t := range trigs {
+			t = strings.TrimSuffix(strings.TrimPrefix(t, "["), "]")
+			if t == v {
+				t = "[" + t + "]"
+			}
+			trigs[i] = t
+		}
+		return strings.Join(trigs, " ")
+	}
+	d.attrs["trigger"].valid = func() []string {
+		var trigs []string
+		for _, t := range strings.Fields(d.attrs["trigger"].value) {
+			trigs = append(trigs, strings.TrimSuffix(strings.TrimPrefix(t, "["), "]"))
+		}
+		return trigs
+	}
+	return d
+}
+
+// AddBattery adds a power supply with the given name, reporting 7.5V and
+// 150mA.
+func (b *Brick) AddBattery(name string) *Device {
+	return b.add(ev3dev.PowerSupplyClass, name, "", map[string]string{
+		"current_now":        "150000",
+		"technology":         "Li-ion",
+		"type":               "Battery",
+		"voltage_max_design": "9000000",
+		"voltage_min_design": "6000000",
+		"voltage_now":        "7500000",
+		"uevent":             "POWER_SUPPLY_NAME=" + name,
+	})
+}
+
+// restrict restricts the valid values of attr to the space separated values
+// of the list attribute.
+func (d *Device) restrict(attr, list string) {
+	d.attrs[attr].valid = func() []string {
+		return strings.Fields(d.attrs[list].value)
+	}
+}
+
+// restrictTo restricts the valid values of attr to values.
+func (d *Device) restrictTo(attr string, values ...string) {
+	d.attrs[attr].valid = func() []string { return values }
+}
+
+// Device returns the device in the Brick with the given class and name, or
+// nil if there is no such device.
+func (b *Brick) Device(class, name string) *Device {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, d := range b.devices {
+		if d.Class == class && d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Root returns the filesystem root of the started or mounted Brick.
+func (b *Brick) Root() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.root
+}
+
+// path returns the filesystem path of the named attribute of d.
+func (d *Device) path(attr string) string {
+	return filepath.Join(d.brick.root, classPaths[d.Class], d.Name, attr)
+}
+
+// Start writes the device tree to regular files in dir and sets the ev3dev
+// filesystem root to dir. If dir is empty, a temporary directory is created
+// and removed by Close.
+//
+// Writes made to a started Brick by code under test are observed by reading
+// the attribute with Attr. Writes are not validated, recorded or reported to
+// OnWrite hooks; use Mount for these.
+func (b *Brick) Start(dir string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.close != nil {
+		return errors.New("ev3devtest: brick already started")
+	}
+
+	remove := dir == ""
+	if remove {
+		var err error
+		dir, err = ioutil.TempDir("", "ev3devtest")
+		if err != nil {
+			return err
+		}
+	}
+	b.root = dir
+	for _, d := range b.devices {
+		for _, n := range d.names {
+			a := d.attrs[n]
+			path := d.path(n)
+			err := os.MkdirAll(filepath.Dir(path), 0755)
+			if err == nil {
+				err = ioutil.WriteFile(path, []byte(a.value+"\n"), fileMode(a.access))
+			}
+			if err != nil {
+				if remove {
+					os.RemoveAll(dir)
+				}
+				b.root = ""
+				return err
+			}
+		}
+	}
+	b.dir = true
+	b.close = func() error {
+		if remove {
+			return os.RemoveAll(dir)
+		}
+		return nil
+	}
+	b.savedRoot = ev3dev.Root()
+	ev3dev.SetRoot(dir)
+	return nil
+}
+
+// fileMode returns the regular file mode for an attribute with the given
+// access. Files are always readable so that writes can be observed.
+func fileMode(a ev3dev.Access) os.FileMode {
+	if a&ev3dev.Write == 0 {
+		return 0444
+	}
+	return 0644
+}
+
+// Close stops serving or removes the device tree and restores the ev3dev
+// filesystem root.
+func (b *Brick) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.close == nil {
+		return nil
+	}
+	ev3dev.SetRoot(b.savedRoot)
+	err := b.close()
+	b.close = nil
+	b.dir = false
+	b.root = ""
+	return err
+}
+
+// Attr returns the current value of the named attribute without a trailing
+// newline. Attr panics if the device does not have the attribute.
+func (d *Device) Attr(name string) string {
+	b := d.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	v, err := d.get(name)
+	if err != nil {
+		panic(fmt.Sprintf("ev3devtest: failed to read %s %s: %v", d.Name, name, err))
+	}
+	return v
+}
+
+// get returns the current value of the named attribute, reading it from
+// its file if the device tree is held in regular files.
+func (d *Device) get(name string) (string, error) {
+	a := d.attr(name)
+	if d.brick.dir {
+		data, err := ioutil.ReadFile(d.path(name))
+		if err != nil {
+			return "", err
+		}
+		a.value = strings.TrimSuffix(string(data), "\n")
+	}
+	return a.value, nil
+}
+
+// SetAttr sets the value of the named attribute as if it had been changed by
+// the device driver. Access restrictions and valid values are not checked.
+// SetAttr panics if the device does not have the attribute.
+func (d *Device) SetAttr(name, value string) error {
+	b := d.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return d.set(name, value)
+}
+
+func (d *Device) set(name, value string) error {
+	a := d.attr(name)
+	a.value = value
+	if !d.brick.dir {
+		return nil
+	}
+	path := d.path(name)
+	if a.access&ev3dev.Write == 0 {
+		err := os.Chmod(path, 0644)
+		if err != nil {
+			return err
+		}
+		defer os.Chmod(path, fileMode(a.access))
+	}
+	return ioutil.WriteFile(path, []byte(value+"\n"), 0)
+}
+
+// SetValues sets the values reported by a sensor and its number of values.
+func (d *Device) SetValues(values ...int) error {
+	b := d.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := d.set("num_values", strconv.Itoa(len(values)))
+	if err != nil {
+		return err
+	}
+	for i, v := range values {
+		err = d.set("value"+strconv.Itoa(i), strconv.Itoa(v))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Device) attr(name string) *attribute {
+	a, ok := d.attrs[name]
+	if !ok {
+		panic(fmt.Sprintf("ev3devtest: %s %s has no attribute %s", d.Class, d.Name, name))
+	}
+	return a
+}
+
+// OnWrite registers a function to be called with the attribute name and
+// value after each successful write to the device by code under test.
+// Hooks are called only when the Brick is mounted.
+func (d *Device) OnWrite(fn func(attr, value string)) {
+	b := d.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d.hooks = append(d.hooks, fn)
+}
+
+// Writes returns the writes made to the device by code under test, in
+// order. Writes are recorded only when the Brick is mounted.
+func (d *Device) Writes() []Write {
+	b := d.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w := make([]Write, len(d.writes))
+	copy(w, d.writes)
+	return w
+}
+
+// Commands returns the values written to the device's command attribute
+// by code under test, in order. Commands are recorded only when the Brick
+// is mounted.
+func (d *Device) Commands() []string {
+	var comms []string
+	for _, w := range d.Writes() {
+		if w.Attr == "command" {
+			comms = append(comms, w.Value)
+		}
+	}
+	return comms
+}
+
+// write handles a write of v to a by code under test.
+func (b *Brick) write(a *attribute, v string) error {
+	b.mu.Lock()
+	if a.valid != nil && !contains(a.valid(), v) {
+		// The ev3dev drivers reject invalid
+		// values with EINVAL.
+		b.mu.Unlock()
+		return syscall.EINVAL
+	}
+	if a.apply != nil {
+		a.value = a.apply(a.value, v)
+	} else {
+		a.value = v
+	}
+	d := a.dev
+	d.writes = append(d.writes, Write{Attr: a.name, Value: v})
+	hooks := make([]func(attr, value string), len(d.hooks))
+	copy(hooks, d.hooks)
+	b.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(a.name, v)
+	}
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3devtest
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/ev3go/ev3dev"
+)
+
+func TestBrickStart(t *testing.T) {
+	b := NewEV3()
+	m := b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor")
+	s := b.AddSensor("ev3-ports:in1", "lego-ev3-color", "COL-REFLECT", "COL-COLOR")
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root := b.Root()
+	defer func() {
+		err := b.Close()
+		if err != nil {
+			t.Errorf("unexpected error closing brick: %v", err)
+		}
+		if _, err := os.Stat(root); !os.IsNotExist(err) {
+			t.Errorf("temporary root not removed: %v", err)
+		}
+		if ev3dev.Root() != "" {
+			t.Errorf("root not restored: %q", ev3dev.Root())
+		}
+	}()
+	if ev3dev.Root() != root {
+		t.Fatalf("unexpected ev3dev root: got:%q want:%q", ev3dev.Root(), root)
+	}
+
+	inv, err := ev3dev.Inventory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, d := range inv {
+		counts[d.Class]++
+	}
+	want := map[string]int{
+		ev3dev.LegoPortClass:    8,
+		ev3dev.SensorClass:      1,
+		ev3dev.TachoMotorClass:  1,
+		ev3dev.LEDClass:         4,
+		ev3dev.PowerSupplyClass: 1,
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("unexpected inventory: got:%v want:%v", counts, want)
+	}
+
+	motor, err := ev3dev.TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = motor.SetSpeedSetpoint(500).SetStopAction("brake").Command("run-forever").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for attr, want := range map[string]string{"speed_sp": "500", "stop_action": "brake", "command": "run-forever"} {
+		if got := m.Attr(attr); got != want {
+			t.Errorf("unexpected %s: got:%q want:%q", attr, got, want)
+		}
+	}
+	err = m.SetAttr("position", "720")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pos, err := motor.Position()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pos != 720 {
+		t.Errorf("unexpected position: got:%d want:720", pos)
+	}
+
+	sensor, err := ev3dev.SensorFor("ev3-ports:in1", "lego-ev3-color")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = s.SetValues(12, 34)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []string{"12", "34"} {
+		got, err := sensor.Value(i)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("unexpected value%d: got:%q want:%q", i, got, want)
+		}
+	}
+
+	// Read-only attributes remain read-only after scripting.
+	fi, err := os.Stat(root + ev3dev.SensorPath + "/sensor0/value0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fi.Mode().Perm() != 0444 {
+		t.Errorf("unexpected mode for value0: got:%v want:%v", fi.Mode().Perm(), os.FileMode(0444))
+	}
+
+	led := ev3dev.LED{Name: ev3dev.LEDName("led0:red:brick-status")}
+	err = led.SetBrightness(200).Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := b.Device(ev3dev.LEDClass, "led0:red:brick-status").Attr("brightness"); got != "200" {
+		t.Errorf("unexpected brightness: got:%q want:%q", got, "200")
+	}
+
+	v, err := ev3dev.PowerSupply("lego-ev3-battery").Voltage()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != 7.5 {
+		t.Errorf("unexpected voltage: got:%v want:7.5", v)
+	}
+}
+
+func TestBrickMount(t *testing.T) {
+	if _, err := exec.LookPath("fusermount"); err != nil {
+		t.Skip("fusermount not available")
+	}
+
+	dir, err := ioutil.TempDir("", "ev3devtest-mount")
+	if err != nil {
+		t.Fatalf("failed to make temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := NewBrick()
+	m := b.AddTachoMotor("ev3-ports:outB", "lego-ev3-m-motor")
+	l := b.AddLED("led0:green:brick-status")
+	var hooked []Write
+	m.OnWrite(func(attr, value string) {
+		hooked = append(hooked, Write{Attr: attr, Value: value})
+	})
+	err = b.Mount(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+
+	motor, err := ev3dev.TachoMotorFor("ev3-ports:outB", "lego-ev3-m-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = motor.SetSpeedSetpoint(100).Command("run-forever").Command("stop").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = ev3dev.WriteAttr(motor, "command", "fly")
+	if err == nil {
+		t.Error("expected error for invalid command")
+	}
+	want := []Write{
+		{Attr: "speed_sp", Value: "100"},
+		{Attr: "command", Value: "run-forever"},
+		{Attr: "command", Value: "stop"},
+	}
+	if got := m.Writes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected writes: got:%v want:%v", got, want)
+	}
+	if !reflect.DeepEqual(hooked, want) {
+		t.Errorf("unexpected hooked writes: got:%v want:%v", hooked, want)
+	}
+	if got, want := m.Commands(), []string{"run-forever", "stop"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected commands: got:%v want:%v", got, want)
+	}
+
+	led := ev3dev.LED{Name: ev3dev.LEDName("led0:green:brick-status")}
+	err = led.SetTrigger("timer").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := l.Attr("trigger"), "none [timer] heartbeat default-on"; got != want {
+		t.Errorf("unexpected trigger: got:%q want:%q", got, want)
+	}
+}
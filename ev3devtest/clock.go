@@ -0,0 +1,92 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3devtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+)
+
+var _ ev3dev.Clock = (*Clock)(nil)
+
+// Clock is a virtual clock for use with ev3dev.SetClock. The time of a Clock
+// changes only when it is advanced, either explicitly with Advance or by code
+// under test calling Sleep or After, which advance the clock by the requested
+// duration and return without delay. The simulated motors of the Bricks
+// attached to a Clock are advanced with it, so code that waits on simulated
+// motors runs deterministically and without real delays.
+//
+// Since every sleep advances the clock, a Clock should not be used with code
+// that sleeps in a loop in the background while the code under test waits.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	bricks []*Brick
+	err    error
+}
+
+// NewClock returns a new Clock starting at the given time and advancing the
+// simulations of the given Bricks.
+func NewClock(start time.Time, bricks ...*Brick) *Clock {
+	return &Clock{now: start, bricks: bricks}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance advances the clock and the simulations of its Bricks by d. If d
+// is zero, the Bricks act on pending commands without advancing time.
+func (c *Clock) Advance(d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.advance(d)
+}
+
+func (c *Clock) advance(d time.Duration) error {
+	if d < 0 {
+		d = 0
+	}
+	c.now = c.now.Add(d)
+	for _, b := range c.bricks {
+		err := b.Advance(d)
+		if err != nil {
+			if c.err == nil {
+				c.err = err
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Sleep advances the clock by d and returns.
+func (c *Clock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance(d)
+}
+
+// After advances the clock by d and returns a channel holding the new time.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance(d)
+	t := make(chan time.Time, 1)
+	t <- c.now
+	return t
+}
+
+// Err returns the first error returned by a simulation advanced by the clock.
+func (c *Clock) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
@@ -0,0 +1,189 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3devtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+	"github.com/ev3go/ev3dev/motorutil"
+)
+
+var epoch = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestClockWait(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		load    float64
+		timeout time.Duration
+
+		wantOK       bool
+		wantPosition int
+		minElapsed   time.Duration
+		maxElapsed   time.Duration
+	}{
+		{
+			name:         "complete",
+			timeout:      5 * time.Second,
+			wantOK:       true,
+			wantPosition: 360,
+			minElapsed:   720 * time.Millisecond,
+			maxElapsed:   800 * time.Millisecond,
+		},
+		{
+			name:         "stalled",
+			load:         2,
+			timeout:      2 * time.Second,
+			wantOK:       false,
+			wantPosition: 0,
+			minElapsed:   2 * time.Second,
+			maxElapsed:   2 * time.Second,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := NewBrick()
+			b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor").Simulate(MotorParams{StallLoad: 1}).SetLoad(test.load)
+			err := b.Start("")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer b.Close()
+			clock := NewClock(epoch, b)
+			ev3dev.SetClock(clock)
+			defer ev3dev.SetClock(nil)
+
+			m, err := ev3dev.TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = m.SetSpeedSetpoint(500).SetPositionSetpoint(360).Command("run-to-rel-pos").Err()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = clock.Advance(0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, ok, err := ev3dev.Wait(m, ev3dev.Running, 0, 0, false, test.timeout)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != test.wantOK {
+				t.Errorf("unexpected wait result: got:%t want:%t", ok, test.wantOK)
+			}
+			err = clock.Err()
+			if err != nil {
+				t.Fatalf("unexpected simulation error: %v", err)
+			}
+			elapsed := clock.Now().Sub(epoch)
+			if elapsed < test.minElapsed || test.maxElapsed < elapsed {
+				t.Errorf("unexpected elapsed time: got:%v want:[%v,%v]", elapsed, test.minElapsed, test.maxElapsed)
+			}
+			pos, err := m.Position()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pos != test.wantPosition {
+				t.Errorf("unexpected position: got:%d want:%d", pos, test.wantPosition)
+			}
+		})
+	}
+}
+
+func TestClockWaitForever(t *testing.T) {
+	b := NewBrick()
+	b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor").Simulate(MotorParams{})
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+	clock := NewClock(epoch, b)
+	ev3dev.SetClock(clock)
+	defer ev3dev.SetClock(nil)
+
+	m, err := ev3dev.TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = m.SetSpeedSetpoint(500).SetTimeSetpoint(time.Second).Command("run-timed").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = clock.Advance(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, ok, err := ev3dev.Wait(m, ev3dev.Running, 0, 0, false, -1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("unexpected wait failure")
+	}
+	err = clock.Err()
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	elapsed := clock.Now().Sub(epoch)
+	if elapsed < time.Second || 1100*time.Millisecond < elapsed {
+		t.Errorf("unexpected elapsed time: got:%v want:[%v,%v]", elapsed, time.Second, 1100*time.Millisecond)
+	}
+}
+
+func TestClockSteering(t *testing.T) {
+	b := NewEV3()
+	b.AddTachoMotor("ev3-ports:outB", "lego-ev3-l-motor").Simulate(MotorParams{Inertia: 20 * time.Millisecond})
+	b.AddTachoMotor("ev3-ports:outC", "lego-ev3-l-motor").Simulate(MotorParams{Inertia: 20 * time.Millisecond})
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+	clock := NewClock(epoch, b)
+	ev3dev.SetClock(clock)
+	defer ev3dev.SetClock(nil)
+
+	var s motorutil.Steering
+	s.Left, err = ev3dev.TachoMotorFor("ev3-ports:outB", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Right, err = ev3dev.TachoMotorFor("ev3-ports:outC", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Timeout = 10 * time.Second
+
+	err = s.SetStopAction("hold").SteerCounts(500, 0, 720).Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = clock.Advance(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = s.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = clock.Err()
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	for _, m := range []*ev3dev.TachoMotor{s.Left, s.Right} {
+		pos, err := m.Position()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pos != 720 {
+			t.Errorf("unexpected position for %s: got:%d want:720", m, pos)
+		}
+	}
+	if elapsed := clock.Now().Sub(epoch); elapsed > s.Timeout {
+		t.Errorf("unexpected elapsed time: %v", elapsed)
+	}
+}
@@ -0,0 +1,466 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3devtest
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+)
+
+// MotorParams holds the physical parameters of a simulated tacho-motor.
+type MotorParams struct {
+	// MaxSpeed is the unloaded speed of the motor
+	// at full power in tacho counts per second. If
+	// MaxSpeed is zero, the max_speed attribute of
+	// the motor is used, otherwise max_speed is set
+	// to MaxSpeed.
+	MaxSpeed int
+
+	// Inertia is the time constant of the motor's
+	// response to a change in its target speed.
+	// When coasting, the motor slows with four
+	// times this time constant. If Inertia is zero
+	// the motor reaches its target speed at once.
+	Inertia time.Duration
+
+	// StallLoad is the load at which the motor
+	// stalls. The speed the motor can reach falls
+	// linearly from MaxSpeed at no load to zero at
+	// the stall load. If StallLoad is zero, the
+	// load set by SetLoad has no effect.
+	StallLoad float64
+}
+
+// coastFactor is the ratio of the coasting time
+// constant to the motor's inertia.
+const coastFactor = 4
+
+// maxStep is the longest simulation step.
+const maxStep = 5 * time.Millisecond
+
+// Motor is a simulated tacho-motor. A Motor evolves the position, speed,
+// duty_cycle and state attributes of its device in response to commands
+// written by code under test, honouring the speed_sp, position_sp, time_sp,
+// duty_cycle_sp, ramp_up_sp, ramp_down_sp and stop_action attributes.
+//
+// The simulation is advanced in virtual time with the Brick's Advance method
+// or a Clock, or in real time with its Run method. When the Brick is mounted,
+// commands are acted on as they are written. When the Brick is started in a
+// directory, commands are acted on at the start of the next simulation step
+// and the command attribute is then cleared; Advance(0) acts on pending
+// commands without advancing time.
+type Motor struct {
+	dev    *Device
+	params MotorParams
+
+	load float64
+
+	// pending holds commands written since
+	// the last simulation step.
+	pending []string
+
+	// pos and speed are the position and speed
+	// of the motor in counts and counts per second.
+	pos, speed float64
+
+	// position is the last reported position.
+	position string
+
+	// command is the command being executed
+	// by the motor, and is empty when idle.
+	command string
+
+	// target is the speed target and goal is
+	// the position goal of the running command.
+	// remain is the time remaining for a timed
+	// run.
+	target float64
+	goal   float64
+	remain time.Duration
+
+	rampUp, rampDown time.Duration
+	stopAction       string
+
+	// holding is true when the motor is
+	// actively holding its position.
+	holding bool
+}
+
+// Simulate attaches a physical simulation to the tacho-motor d, which must
+// have been added to a Brick that has not been started or mounted.
+func (d *Device) Simulate(p MotorParams) *Motor {
+	if d.Class != ev3dev.TachoMotorClass {
+		panic("ev3devtest: simulation of " + d.Class)
+	}
+	b := d.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.close != nil {
+		panic("ev3devtest: simulation added to started brick")
+	}
+	if p.MaxSpeed == 0 {
+		p.MaxSpeed, _ = strconv.Atoi(d.attrs["max_speed"].value)
+	} else {
+		d.attrs["max_speed"].value = strconv.Itoa(p.MaxSpeed)
+	}
+	m := &Motor{
+		dev:        d,
+		params:     p,
+		position:   d.attrs["position"].value,
+		stopAction: d.attrs["stop_action"].value,
+	}
+	pos, _ := strconv.Atoi(m.position)
+	m.pos = float64(pos)
+	d.hooks = append(d.hooks, func(attr, value string) {
+		if attr != "command" {
+			return
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		m.pending = append(m.pending, value)
+		// The step cannot fail since the attributes
+		// of a mounted Brick are held in memory.
+		m.step(0)
+	})
+	b.motors = append(b.motors, m)
+	return m
+}
+
+// SetLoad sets the external load on the motor in the units of the
+// motor's StallLoad parameter.
+func (m *Motor) SetLoad(load float64) {
+	b := m.dev.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m.load = load
+}
+
+// Advance advances the simulated motors of the Brick by the duration dt
+// of virtual time. If dt is zero, pending commands are acted on without
+// advancing time.
+func (b *Brick) Advance(dt time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		step := dt
+		if step > maxStep {
+			step = maxStep
+		}
+		if step < 0 {
+			step = 0
+		}
+		for _, m := range b.motors {
+			err := m.step(step)
+			if err != nil {
+				return err
+			}
+		}
+		dt -= step
+		if dt <= 0 {
+			return nil
+		}
+	}
+}
+
+// Run advances the simulated motors of the Brick in real time, at intervals
+// of tick, until the returned stop function is called. The stop function
+// returns the first error encountered by the simulation.
+//
+// Run is intended for code under test that waits in real time, such as
+// ev3dev.Wait. When the Brick is started in a directory, code under test
+// may observe a partially written attribute while the simulation is running;
+// mount the Brick to avoid this.
+func (b *Brick) Run(tick time.Duration) (stop func() error) {
+	done := make(chan struct{})
+	var (
+		err  error
+		wg   sync.WaitGroup
+		once sync.Once
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		t := time.NewTicker(tick)
+		defer t.Stop()
+		last := time.Now()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-t.C:
+				err = b.Advance(now.Sub(last))
+				if err != nil {
+					return
+				}
+				last = now
+			}
+		}
+	}()
+	return func() error {
+		once.Do(func() { close(done) })
+		wg.Wait()
+		return err
+	}
+}
+
+// step advances the motor by dt. It must be called with the brick's
+// lock held.
+func (m *Motor) step(dt time.Duration) error {
+	d := m.dev
+
+	// Take up position changes made
+	// by code under test.
+	v, err := d.get("position")
+	if err != nil {
+		return err
+	}
+	if v != m.position {
+		pos, err := strconv.Atoi(v)
+		if err == nil {
+			m.pos = float64(pos)
+		}
+		m.position = v
+	}
+
+	if d.brick.dir {
+		c, err := d.get("command")
+		if err != nil {
+			return err
+		}
+		if c != "" {
+			m.pending = append(m.pending, c)
+			err = d.set("command", "")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range m.pending {
+		err = m.start(c)
+		if err != nil {
+			return err
+		}
+	}
+	m.pending = m.pending[:0]
+
+	state, duty, err := m.advance(dt)
+	if err != nil {
+		return err
+	}
+	return m.report(state, duty)
+}
+
+// attrReader reads integer and string attributes from a device, holding
+// the first error encountered.
+type attrReader struct {
+	dev *Device
+	err error
+}
+
+func (r *attrReader) str(name string) string {
+	if r.err != nil {
+		return ""
+	}
+	var v string
+	v, r.err = r.dev.get(name)
+	return v
+}
+
+func (r *attrReader) int(name string) int {
+	v := r.str(name)
+	if r.err != nil {
+		return 0
+	}
+	var i int
+	i, r.err = strconv.Atoi(v)
+	return i
+}
+
+// start starts executing the motor command c.
+func (m *Motor) start(c string) error {
+	r := attrReader{dev: m.dev}
+	switch c {
+	case "run-forever", "run-timed", "run-to-abs-pos", "run-to-rel-pos", "run-direct":
+		m.target = float64(r.int("speed_sp"))
+		m.rampUp = time.Duration(r.int("ramp_up_sp")) * time.Millisecond
+		m.rampDown = time.Duration(r.int("ramp_down_sp")) * time.Millisecond
+		m.stopAction = r.str("stop_action")
+		switch c {
+		case "run-timed":
+			m.remain = time.Duration(r.int("time_sp")) * time.Millisecond
+		case "run-to-abs-pos":
+			m.goal = float64(r.int("position_sp"))
+		case "run-to-rel-pos":
+			m.goal = m.pos + float64(r.int("position_sp"))
+		}
+		if r.err != nil {
+			return r.err
+		}
+		m.command = c
+		m.holding = false
+	case "stop":
+		m.stopAction = r.str("stop_action")
+		if r.err != nil {
+			return r.err
+		}
+		m.halt()
+	case "reset":
+		for _, a := range []string{"duty_cycle_sp", "position_sp", "ramp_down_sp", "ramp_up_sp", "speed_sp", "time_sp"} {
+			err := m.dev.set(a, "0")
+			if err != nil {
+				return err
+			}
+		}
+		err := m.dev.set("stop_action", "coast")
+		if err != nil {
+			return err
+		}
+		err = m.dev.set("polarity", string(ev3dev.Normal))
+		if err != nil {
+			return err
+		}
+		m.pos = 0
+		m.speed = 0
+		m.command = ""
+		m.stopAction = "coast"
+		m.holding = false
+	}
+	return nil
+}
+
+// halt stops the motor according to its stop action.
+func (m *Motor) halt() {
+	m.command = "stop"
+	if m.stopAction == "hold" {
+		m.speed = 0
+		m.holding = true
+	}
+}
+
+// advance advances the motor's dynamics by dt, returning the motor state
+// and duty cycle.
+func (m *Motor) advance(dt time.Duration) (state []string, duty float64, err error) {
+	maxSpeed := float64(m.params.MaxSpeed)
+	running := strings.HasPrefix(m.command, "run-")
+
+	var target float64
+	switch m.command {
+	case "run-forever", "run-timed":
+		target = m.target
+	case "run-to-abs-pos", "run-to-rel-pos":
+		target = math.Copysign(math.Abs(m.target), m.goal-m.pos)
+	case "run-direct":
+		r := attrReader{dev: m.dev}
+		target = maxSpeed * float64(r.int("duty_cycle_sp")) / 100
+		if r.err != nil {
+			return nil, 0, r.err
+		}
+	}
+	if running && maxSpeed != 0 {
+		duty = math.Max(-100, math.Min(100, 100*target/maxSpeed))
+	}
+
+	// Load limits the speed the motor can reach.
+	limit := maxSpeed
+	if m.params.StallLoad > 0 {
+		limit *= math.Max(0, 1-m.load/m.params.StallLoad)
+	}
+	overloaded := math.Abs(target) > limit
+	stalled := running && target != 0 && limit == 0
+	if overloaded {
+		target = math.Copysign(limit, target)
+	}
+
+	next := target
+	if m.holding {
+		next = 0
+	} else {
+		tau := m.params.Inertia
+		if !running && m.stopAction == "coast" {
+			tau *= coastFactor
+		}
+		if tau > 0 {
+			next = m.speed + (target-m.speed)*(1-math.Exp(-dt.Seconds()/tau.Seconds()))
+		}
+	}
+
+	var ramping bool
+	if running {
+		ramp := m.rampUp
+		if math.Abs(next) < math.Abs(m.speed) {
+			ramp = m.rampDown
+		}
+		if ramp > 0 {
+			max := maxSpeed * dt.Seconds() / ramp.Seconds()
+			if delta := next - m.speed; math.Abs(delta) > max {
+				next = m.speed + math.Copysign(max, delta)
+				ramping = true
+			}
+		}
+	}
+
+	last := m.pos
+	m.pos += next * dt.Seconds()
+	m.speed = next
+
+	switch m.command {
+	case "run-timed":
+		m.remain -= dt
+		if m.remain <= 0 {
+			m.halt()
+		}
+	case "run-to-abs-pos", "run-to-rel-pos":
+		if (m.goal-last)*(m.goal-m.pos) <= 0 {
+			m.pos = m.goal
+			m.halt()
+		}
+	}
+
+	if running && !strings.HasPrefix(m.command, "run-") {
+		running = false
+		duty = 0
+	}
+	switch {
+	case running:
+		state = append(state, "running")
+		if ramping {
+			state = append(state, "ramping")
+		}
+		if stalled {
+			state = append(state, "stalled")
+		} else if overloaded && target != 0 {
+			state = append(state, "overloaded")
+		}
+	case m.holding:
+		state = append(state, "holding")
+	}
+	return state, duty, nil
+}
+
+// report writes the motor's position, speed, duty cycle and state to its
+// device's attributes.
+func (m *Motor) report(state []string, duty float64) error {
+	m.position = strconv.Itoa(int(math.Round(m.pos)))
+	for _, a := range []struct{ name, value string }{
+		{name: "position", value: m.position},
+		{name: "speed", value: strconv.Itoa(int(math.Round(m.speed)))},
+		{name: "duty_cycle", value: strconv.Itoa(int(math.Round(duty)))},
+		{name: "state", value: strings.Join(state, " ")},
+	} {
+		if m.dev.attrs[a.name].value == a.value {
+			continue
+		}
+		err := m.dev.set(a.name, a.value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
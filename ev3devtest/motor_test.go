@@ -0,0 +1,302 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3devtest
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+)
+
+var motorTests = []struct {
+	name   string
+	params MotorParams
+	load   float64
+	setup  func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor
+	after  time.Duration
+
+	wantPosition int
+	wantSpeed    int
+	wantState    ev3dev.MotorState
+	tol          int
+}{
+	{
+		name: "run-forever",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(500).Command("run-forever")
+		},
+		after:        time.Second,
+		wantPosition: 500,
+		wantSpeed:    500,
+		wantState:    ev3dev.Running,
+	},
+	{
+		name: "run-forever reverse",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(-200).Command("run-forever")
+		},
+		after:        2 * time.Second,
+		wantPosition: -400,
+		wantSpeed:    -200,
+		wantState:    ev3dev.Running,
+	},
+	{
+		name: "run-timed",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(400).SetTimeSetpoint(500 * time.Millisecond).Command("run-timed")
+		},
+		after:        time.Second,
+		wantPosition: 200,
+		wantSpeed:    0,
+		wantState:    0,
+	},
+	{
+		name: "run-to-rel-pos",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(500).SetPositionSetpoint(-360).Command("run-to-rel-pos")
+		},
+		after:        time.Second,
+		wantPosition: -360,
+		wantSpeed:    0,
+		wantState:    0,
+	},
+	{
+		name: "run-to-abs-pos hold",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(500).SetPositionSetpoint(90).SetStopAction("hold").Command("run-to-abs-pos")
+		},
+		after:        time.Second,
+		wantPosition: 90,
+		wantSpeed:    0,
+		wantState:    ev3dev.Holding,
+	},
+	{
+		name: "run-direct",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetDutyCycleSetpoint(50).Command("run-direct")
+		},
+		after:        time.Second,
+		wantPosition: 525,
+		wantSpeed:    525,
+		wantState:    ev3dev.Running,
+	},
+	{
+		name: "ramp up",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(1050).SetRampUpSetpoint(time.Second).Command("run-forever")
+		},
+		after:        500 * time.Millisecond,
+		wantPosition: 131,
+		wantSpeed:    525,
+		wantState:    ev3dev.Running | ev3dev.Ramping,
+		tol:          2,
+	},
+	{
+		name:   "inertia",
+		params: MotorParams{Inertia: 100 * time.Millisecond},
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(1000).Command("run-forever")
+		},
+		after:        100 * time.Millisecond,
+		wantPosition: 37,
+		wantSpeed:    632,
+		wantState:    ev3dev.Running,
+		tol:          2,
+	},
+	{
+		name:   "overloaded",
+		params: MotorParams{StallLoad: 1},
+		load:   0.5,
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(1000).Command("run-forever")
+		},
+		after:        time.Second,
+		wantPosition: 525,
+		wantSpeed:    525,
+		wantState:    ev3dev.Running | ev3dev.Overloaded,
+	},
+	{
+		name:   "stalled",
+		params: MotorParams{StallLoad: 1},
+		load:   1,
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(1000).Command("run-forever")
+		},
+		after:        time.Second,
+		wantPosition: 0,
+		wantSpeed:    0,
+		wantState:    ev3dev.Running | ev3dev.Stalled,
+	},
+	{
+		name: "reset",
+		setup: func(m *ev3dev.TachoMotor) *ev3dev.TachoMotor {
+			return m.SetSpeedSetpoint(1000).SetPosition(100).Command("reset")
+		},
+		after:        time.Second,
+		wantPosition: 0,
+		wantSpeed:    0,
+		wantState:    0,
+	},
+}
+
+func TestMotor(t *testing.T) {
+	for _, test := range motorTests {
+		t.Run(test.name, func(t *testing.T) {
+			b := NewBrick()
+			sim := b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor").Simulate(test.params)
+			sim.SetLoad(test.load)
+			err := b.Start("")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer b.Close()
+
+			m, err := ev3dev.TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = test.setup(m).Err()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = b.Advance(test.after)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			pos, err := m.Position()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !near(pos, test.wantPosition, test.tol) {
+				t.Errorf("unexpected position: got:%d want:%d", pos, test.wantPosition)
+			}
+			speed, err := m.Speed()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !near(speed, test.wantSpeed, test.tol) {
+				t.Errorf("unexpected speed: got:%d want:%d", speed, test.wantSpeed)
+			}
+			stat, err := m.State()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stat != test.wantState {
+				t.Errorf("unexpected state: got:%v want:%v", stat, test.wantState)
+			}
+		})
+	}
+}
+
+func near(got, want, tol int) bool {
+	return math.Abs(float64(got-want)) <= float64(tol)
+}
+
+func TestMotorStop(t *testing.T) {
+	for _, test := range []struct {
+		action string
+		want   int
+	}{
+		{action: "coast", want: 400},
+		{action: "brake", want: 100},
+		{action: "hold", want: 0},
+	} {
+		b := NewBrick()
+		b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor").Simulate(MotorParams{Inertia: 100 * time.Millisecond})
+		err := b.Start("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		m, err := ev3dev.TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = m.SetSpeedSetpoint(1000).SetStopAction(test.action).Command("run-forever").Err()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = b.Advance(time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		start, err := m.Position()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = m.Command("stop").Err()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = b.Advance(2 * time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		end, err := m.Position()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// The motor runs on by approximately the
+		// product of its speed and time constant.
+		if !near(end-start, test.want, 5) {
+			t.Errorf("unexpected run on with %s: got:%d want:%d", test.action, end-start, test.want)
+		}
+		b.Close()
+	}
+}
+
+func TestMotorWait(t *testing.T) {
+	if _, err := exec.LookPath("fusermount"); err != nil {
+		t.Skip("fusermount not available")
+	}
+
+	dir, err := ioutil.TempDir("", "ev3devtest-mount")
+	if err != nil {
+		t.Fatalf("failed to make temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := NewBrick()
+	b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor").Simulate(MotorParams{Inertia: 20 * time.Millisecond})
+	err = b.Mount(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+	stop := b.Run(5 * time.Millisecond)
+
+	m, err := ev3dev.TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = m.SetSpeedSetpoint(1000).SetPositionSetpoint(180).SetStopAction("hold").Command("run-to-rel-pos").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stat, ok, err := ev3dev.Wait(m, ev3dev.Running, 0, 0, false, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Errorf("motor did not stop: state %v", stat)
+	}
+	err = stop()
+	if err != nil {
+		t.Fatalf("unexpected simulation error: %v", err)
+	}
+	pos, err := m.Position()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pos != 180 {
+		t.Errorf("unexpected position: got:%d want:180", pos)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3devtest
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/ev3go/sisyphus"
+
+	"github.com/ev3go/ev3dev"
+)
+
+// Mount serves the device tree as a FUSE filesystem mounted at dir and sets
+// the ev3dev filesystem root to dir. The directory must exist.
+//
+// Writes made to a mounted Brick by code under test are validated against
+// the valid values for the attribute, recorded and reported to OnWrite hooks.
+func (b *Brick) Mount(dir string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.close != nil {
+		return errors.New("ev3devtest: brick already started")
+	}
+
+	classes := make(map[string][]sisyphus.Node)
+	var order []string
+	for _, d := range b.devices {
+		path := classPaths[d.Class]
+		if _, ok := classes[path]; !ok {
+			order = append(order, path)
+		}
+		classes[path] = append(classes[path], d.node())
+	}
+
+	// All class paths are in /sys/class.
+	var nodes []sisyphus.Node
+	for _, path := range order {
+		nodes = append(nodes, sisyphus.MustNewDir(filepath.Base(path), 0775).With(classes[path]...))
+	}
+	fs := sisyphus.NewFileSystem(0775, time.Now).With(
+		sisyphus.MustNewDir("sys", 0775).With(
+			sisyphus.MustNewDir("class", 0775).With(nodes...),
+		),
+	).Sync()
+
+	c, err := sisyphus.Serve(dir, fs, nil, fuse.AllowNonEmptyMount())
+	if err != nil {
+		return err
+	}
+	b.root = dir
+	b.close = c.Close
+	b.savedRoot = ev3dev.Root()
+	ev3dev.SetRoot(dir)
+	return nil
+}
+
+// node returns a sisyphus directory node for the device.
+func (d *Device) node() sisyphus.Node {
+	dir := sisyphus.MustNewDir(d.Name, 0775)
+	var files []sisyphus.Node
+	subdirs := make(map[string][]sisyphus.Node)
+	var order []string
+	for _, n := range d.names {
+		a := d.attrs[n]
+		f := a.node(filepath.Base(n))
+		if i := strings.Index(n, "/"); i >= 0 {
+			sub := n[:i]
+			if _, ok := subdirs[sub]; !ok {
+				order = append(order, sub)
+			}
+			subdirs[sub] = append(subdirs[sub], f)
+			continue
+		}
+		files = append(files, f)
+	}
+	for _, sub := range order {
+		files = append(files, sisyphus.MustNewDir(sub, 0775).With(subdirs[sub]...))
+	}
+	return dir.With(files...)
+}
+
+// node returns a sisyphus file node for the attribute.
+func (a *attribute) node(name string) sisyphus.Node {
+	switch a.access {
+	case ev3dev.Read:
+		return sisyphus.MustNewRO(name, 0444, a)
+	case ev3dev.Write:
+		return sisyphus.MustNewWO(name, 0222, a)
+	default:
+		return sisyphus.MustNewRW(name, 0666, a)
+	}
+}
+
+// ReadAt satisfies the io.ReaderAt interface.
+func (a *attribute) ReadAt(b []byte, offset int64) (int, error) {
+	s := a.String() + "\n"
+	if offset >= int64(len(s)) {
+		return 0, io.EOF
+	}
+	n := copy(b, s[offset:])
+	if offset+int64(n) == int64(len(s)) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt satisfies the io.WriterAt interface.
+func (a *attribute) WriteAt(b []byte, offset int64) (int, error) {
+	err := a.dev.brick.write(a, strings.TrimSuffix(string(b), "\n"))
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Truncate is a no-op, as for sysfs attributes.
+func (a *attribute) Truncate(int64) error { return nil }
+
+// Size returns the length of the attribute's value and a nil error.
+func (a *attribute) Size() (int64, error) {
+	return int64(len(a.String()) + 1), nil
+}
+
+// String returns the attribute's value.
+func (a *attribute) String() string {
+	b := a.dev.brick
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return a.value
+}
@@ -0,0 +1,15 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !linux
+
+package ev3devtest
+
+import "errors"
+
+// Mount serves the device tree as a FUSE filesystem mounted at dir and sets
+// the ev3dev filesystem root to dir. Mount is only supported on linux.
+func (b *Brick) Mount(dir string) error {
+	return errors.New("ev3devtest: mount not supported on this system")
+}
@@ -6,13 +6,29 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 
 	"github.com/ev3go/ev3dev/motorutil"
 )
 
 func main() {
-	err := motorutil.ResetAll()
+	dryRun := flag.Bool("n", false, "report the actions that would be taken without taking them")
+	flag.Parse()
+
+	if !*dryRun {
+		err := motorutil.ResetAll()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	report, err := motorutil.Reset(&motorutil.ResetOptions{DryRun: true})
+	for _, a := range report {
+		fmt.Printf("%s %s (%s %s): %s\n", a.Class, a.Device, a.Address, a.Driver, a.Action)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -7,7 +7,7 @@ package ev3dev
 var Prefix string
 
 func init() {
-	prefix = "testmount"
+	SetRoot("testmount")
 	Prefix = prefix
 
 	// We cannot use poll(2) for waiting on motor state attribute in testing.
@@ -0,0 +1,44 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTree writes the given attribute files relative to root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	for path, data := range files {
+		path = filepath.Join(root, path)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("failed to make directory: %v", err)
+		}
+		err = ioutil.WriteFile(path, []byte(data), 0644)
+		if err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
+
+// fakeRoot writes the given attribute files into a new temporary
+// directory and makes it the sysfs root. The returned func restores
+// the previous root and removes the directory.
+func fakeRoot(t *testing.T, files map[string]string) (root string, done func()) {
+	root, err := ioutil.TempDir("", "ev3dev-test")
+	if err != nil {
+		t.Fatalf("failed to make temporary directory: %v", err)
+	}
+	writeTree(t, root, files)
+	saved := prefix
+	prefix = root
+	return root, func() {
+		prefix = saved
+		os.RemoveAll(root)
+	}
+}
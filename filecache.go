@@ -0,0 +1,254 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// fileCache holds open attribute files for a device handle so that
+// repeated reads and writes can be made with pread(2) and pwrite(2)
+// rather than opening and closing the attribute file for each access.
+type fileCache struct {
+	mu sync.Mutex
+
+	// attrs is the set of attributes
+	// that are held open.
+	attrs map[string]bool
+
+	readers map[string]*cachedFile
+	writers map[string]*cachedFile
+
+	buf []byte
+}
+
+// cachedFile is an open attribute file.
+type cachedFile struct {
+	path string
+	f    *os.File
+
+	// size is the size of the file after
+	// the last write, or its size when
+	// opened if it has not been written.
+	size int64
+}
+
+// newFileCache returns a fileCache holding the given attributes open.
+func newFileCache(attrs ...string) *fileCache {
+	c := fileCache{
+		attrs:   make(map[string]bool, len(attrs)),
+		readers: make(map[string]*cachedFile),
+		writers: make(map[string]*cachedFile),
+
+		// Sysfs attribute values are at most
+		// a page in length.
+		buf: make([]byte, 4096),
+	}
+	for _, a := range attrs {
+		c.attrs[a] = true
+	}
+	return &c
+}
+
+// read reads the attribute at path. The returned bool is false if attr is
+// not held open by the cache, in which case the caller must read the file
+// directly.
+func (c *fileCache) read(path, attr string) (b []byte, ok bool, err error) {
+	if c == nil || !c.attrs[attr] {
+		return nil, false, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cf, err := c.open(c.readers, path, attr, os.O_RDONLY)
+	if err != nil {
+		return nil, true, err
+	}
+	n, err := cf.f.ReadAt(c.buf, 0)
+	if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		c.drop(c.readers, attr)
+		return nil, true, err
+	}
+	b = make([]byte, n)
+	copy(b, c.buf)
+	return b, true, nil
+}
+
+// write writes data to the attribute at path. The returned bool is false
+// if attr is not held open by the cache, in which case the caller must
+// write the file directly.
+func (c *fileCache) write(path, attr, data string) (ok bool, err error) {
+	if c == nil || !c.attrs[attr] {
+		return false, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cf, err := c.open(c.writers, path, attr, os.O_WRONLY)
+	if err != nil {
+		return true, err
+	}
+	_, err = cf.f.WriteAt([]byte(data), 0)
+	if err == nil && cf.size > int64(len(data)) {
+		// Sysfs ignores the file size, but an
+		// alternative filesystem root may be
+		// backed by regular files.
+		err = cf.f.Truncate(int64(len(data)))
+	}
+	if err != nil {
+		c.drop(c.writers, attr)
+		return true, err
+	}
+	cf.size = int64(len(data))
+	return true, nil
+}
+
+// open returns the cached file for attr in files, opening it with the given
+// flag if it is not open or was opened for a different path.
+func (c *fileCache) open(files map[string]*cachedFile, path, attr string, flag int) (*cachedFile, error) {
+	cf, ok := files[attr]
+	if ok && cf.path == path {
+		return cf, nil
+	}
+	if ok {
+		c.drop(files, attr)
+	}
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	cf = &cachedFile{path: path, f: f, size: fi.Size()}
+	files[attr] = cf
+	return cf, nil
+}
+
+// drop closes and removes the cached file for attr in files.
+func (c *fileCache) drop(files map[string]*cachedFile, attr string) error {
+	cf, ok := files[attr]
+	if !ok {
+		return nil
+	}
+	delete(files, attr)
+	return cf.f.Close()
+}
+
+// invalidate closes all the files held by the cache. Files are reopened
+// on their next use.
+func (c *fileCache) invalidate() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for _, files := range []map[string]*cachedFile{c.readers, c.writers} {
+		for attr := range files {
+			_err := c.drop(files, attr)
+			if err == nil {
+				err = _err
+			}
+		}
+	}
+	return err
+}
+
+// fileCacher is a Device that may hold attribute files open.
+type fileCacher interface {
+	Device
+
+	// fileCache returns the file cache of the
+	// device, or nil if files are not held open.
+	fileCache() *fileCache
+}
+
+// fileCacheOf returns the file cache of d or nil if d does not hold
+// attribute files open.
+func fileCacheOf(d Device) *fileCache {
+	fc, ok := d.(fileCacher)
+	if !ok {
+		return nil
+	}
+	return fc.fileCache()
+}
+
+// tachoMotorCachedAttrs are the attributes held open by a TachoMotor
+// when KeepFilesOpen is enabled.
+var tachoMotorCachedAttrs = []string{position, speed, dutyCycle, dutyCycleSetpoint, state}
+
+// KeepFilesOpen sets whether the TachoMotor holds its position, speed,
+// duty_cycle, duty_cycle_sp and state attribute files open between calls.
+// When files are held open, reads and writes of these attributes use pread(2)
+// and pwrite(2) on the open file, avoiding the cost of opening and closing the
+// file for each access in high-rate control loops.
+//
+// Open files are closed when the TachoMotor is bound to a different device,
+// when an access fails and when the TachoMotor is released by Release. Files
+// are reopened on their next use. Calling KeepFilesOpen with keep false closes
+// any open files and returns any error from closing them.
+func (m *TachoMotor) KeepFilesOpen(keep bool) error {
+	if !keep {
+		err := m.files.invalidate()
+		m.files = nil
+		return err
+	}
+	if m.files == nil {
+		m.files = newFileCache(tachoMotorCachedAttrs...)
+	}
+	return nil
+}
+
+func (m *TachoMotor) fileCache() *fileCache {
+	if m == nil {
+		return nil
+	}
+	return m.files
+}
+
+// maxSensorValues is the maximum number of value attributes for a sensor.
+const maxSensorValues = 8
+
+// KeepFilesOpen sets whether the Sensor holds its value0 to value7 attribute
+// files open between calls. When files are held open, reads of these attributes
+// use pread(2) on the open file, avoiding the cost of opening and closing the
+// file for each access in high-rate control loops.
+//
+// Open files are closed when the Sensor is bound to a different device, when
+// its mode is set, when an access fails and when the Sensor is released by
+// Release. Files are reopened on their next use. Calling KeepFilesOpen with
+// keep false closes any open files and returns any error from closing them.
+func (s *Sensor) KeepFilesOpen(keep bool) error {
+	if !keep {
+		err := s.files.invalidate()
+		s.files = nil
+		return err
+	}
+	if s.files == nil {
+		attrs := make([]string, maxSensorValues)
+		for i := range attrs {
+			attrs[i] = value + strconv.Itoa(i)
+		}
+		s.files = newFileCache(attrs...)
+	}
+	return nil
+}
+
+func (s *Sensor) fileCache() *fileCache {
+	if s == nil {
+		return nil
+	}
+	return s.files
+}
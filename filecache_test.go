@@ -0,0 +1,173 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestKeepFilesOpen(t *testing.T) {
+	const driver = "lego-ev3-l-motor"
+	motor := func(name, addr string) map[string]string {
+		dir := filepath.Join(TachoMotorPath, name)
+		return map[string]string{
+			filepath.Join(dir, address):           addr + "\n",
+			filepath.Join(dir, driverName):        driver + "\n",
+			filepath.Join(dir, countPerRot):       "360\n",
+			filepath.Join(dir, maxSpeed):          "1050\n",
+			filepath.Join(dir, commands):          "run-forever run-direct stop reset\n",
+			filepath.Join(dir, stopActions):       "coast brake hold\n",
+			filepath.Join(dir, position):          "0\n",
+			filepath.Join(dir, speed):             "0\n",
+			filepath.Join(dir, dutyCycleSetpoint): "0\n",
+		}
+	}
+	root, done := fakeRoot(t, motor("motor0", "ev3-ports:outA"))
+	defer done()
+	writeTree(t, root, motor("motor1", "ev3-ports:outB"))
+
+	m, err := TachoMotorFor("ev3-ports:outA", driver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer Release(m)
+	err = m.KeepFilesOpen(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	attr := func(name, attr string) string {
+		b, err := ioutil.ReadFile(filepath.Join(root, TachoMotorPath, name, attr))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", attr, err)
+		}
+		return string(b)
+	}
+	setAttr := func(name, attr, data string) {
+		err := ioutil.WriteFile(filepath.Join(root, TachoMotorPath, name, attr), []byte(data), 0644)
+		if err != nil {
+			t.Fatalf("failed to write %s: %v", attr, err)
+		}
+	}
+
+	for _, want := range []int{0, 1234, -5} {
+		setAttr("motor0", position, strconv.Itoa(want)+"\n")
+		got, err := m.Position()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("unexpected position: got:%d want:%d", got, want)
+		}
+	}
+	if n := len(m.files.readers); n != 1 {
+		t.Errorf("unexpected number of open readers: got:%d want:1", n)
+	}
+
+	for _, sp := range []int{-100, 50, 5} {
+		err = m.SetDutyCycleSetpoint(sp).Err()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := attr("motor0", dutyCycleSetpoint), strconv.Itoa(sp); got != want {
+			t.Errorf("unexpected duty_cycle_sp: got:%q want:%q", got, want)
+		}
+	}
+	if n := len(m.files.writers); n != 1 {
+		t.Errorf("unexpected number of open writers: got:%d want:1", n)
+	}
+
+	// Uncached attributes are not held open.
+	err = m.Command("run-direct").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := m.files.writers[command]; ok {
+		t.Error("unexpected open command file")
+	}
+
+	// Rebinding the handle closes files for the old device.
+	err = m.setID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(m.files.readers) + len(m.files.writers); n != 0 {
+		t.Errorf("unexpected number of open files after setID: got:%d want:0", n)
+	}
+	setAttr("motor1", position, "42\n")
+	got, err := m.Position()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 42 {
+		t.Errorf("unexpected position after setID: got:%d want:42", got)
+	}
+
+	err = m.KeepFilesOpen(false)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if m.files != nil {
+		t.Error("unexpected file cache after disabling")
+	}
+}
+
+func TestSensorKeepFilesOpen(t *testing.T) {
+	const (
+		addr   = "ev3-ports:in1"
+		driver = "lego-ev3-color"
+	)
+	dir := filepath.Join(SensorPath, "sensor0")
+	_, done := fakeRoot(t, map[string]string{
+		filepath.Join(dir, address):         addr + "\n",
+		filepath.Join(dir, driverName):      driver + "\n",
+		filepath.Join(dir, firmwareVersion): "\n",
+		filepath.Join(dir, commands):        "\n",
+		filepath.Join(dir, modes):           "COL-REFLECT COL-COLOR\n",
+		filepath.Join(dir, mode):            "COL-REFLECT\n",
+		filepath.Join(dir, decimals):        "0\n",
+		filepath.Join(dir, numValues):       "1\n",
+		filepath.Join(dir, units):           "pct\n",
+		filepath.Join(dir, binDataFormat):   "s8\n",
+		filepath.Join(dir, value+"0"):       "17\n",
+	})
+	defer done()
+
+	s, err := SensorFor(addr, driver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = s.KeepFilesOpen(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	v, err := s.Value(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != "17" {
+		t.Errorf("unexpected value: got:%q want:%q", v, "17")
+	}
+	if n := len(s.files.readers); n != 1 {
+		t.Errorf("unexpected number of open readers: got:%d want:1", n)
+	}
+
+	err = s.SetMode("COL-COLOR").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(s.files.readers); n != 0 {
+		t.Errorf("unexpected number of open readers after SetMode: got:%d want:0", n)
+	}
+
+	err = s.KeepFilesOpen(false)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
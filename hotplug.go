@@ -0,0 +1,252 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HotplugAction is the kind of change reported by a HotplugEvent.
+type HotplugAction int
+
+const (
+	// DeviceAdded indicates a device was connected.
+	DeviceAdded HotplugAction = iota + 1
+
+	// DeviceRemoved indicates a device was disconnected.
+	DeviceRemoved
+)
+
+// String satisfies the fmt.Stringer interface.
+func (a HotplugAction) String() string {
+	switch a {
+	case DeviceAdded:
+		return "add"
+	case DeviceRemoved:
+		return "remove"
+	default:
+		return "unknown"
+	}
+}
+
+// HotplugEvent is a device connection change. The Err value reflects any
+// error state arising from detecting the event.
+type HotplugEvent struct {
+	Action HotplugAction
+
+	// Class is the device class of the device, one of
+	// LegoPortClass, SensorClass, TachoMotorClass,
+	// LinearActuatorClass, ServoMotorClass or DCMotorClass.
+	Class string
+
+	// Name is the sysfs directory name of the device.
+	Name string
+
+	// Address and Driver are the port address and
+	// driver name of the device.
+	Address string
+	Driver  string
+
+	Err error
+}
+
+// hotplugClasses lists the device classes reported by a HotplugWatcher.
+var hotplugClasses = inventoryClasses[:6]
+
+// HotplugWatcher provides a mechanism to block waiting for device
+// connection changes.
+type HotplugWatcher struct {
+	Events <-chan HotplugEvent
+
+	done    chan struct{}
+	wg      sync.WaitGroup
+	closing sync.Once
+	closeFn func() error
+}
+
+// NewHotplugWatcher returns a HotplugWatcher. When the package is using the
+// system sysfs, the HotplugWatcher listens for kernel uevents on a netlink
+// socket. If netlink is not available, or the package is using an alternative
+// filesystem root, the device class directories are polled at the given
+// interval. If interval is not positive, a default of 200ms is used.
+func NewHotplugWatcher(interval time.Duration) (*HotplugWatcher, error) {
+	if prefix == "" {
+		w, err := newNetlinkWatcher()
+		if err == nil {
+			return w, nil
+		}
+	}
+	return newPollingWatcher(interval)
+}
+
+// Close stops the HotplugWatcher and closes the Events channel.
+// Only the first call to Close has any effect.
+func (w *HotplugWatcher) Close() error {
+	var err error
+	w.closing.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+		if w.closeFn != nil {
+			err = w.closeFn()
+		}
+	})
+	return err
+}
+
+// send sends ev on c unless the watcher is closed, returning false if
+// the watcher has been closed.
+func (w *HotplugWatcher) send(c chan<- HotplugEvent, ev HotplugEvent) bool {
+	select {
+	case c <- ev:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func newPollingWatcher(interval time.Duration) (*HotplugWatcher, error) {
+	if interval <= 0 {
+		interval = 200 * time.Millisecond
+	}
+	last, err := hotplugSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	c := make(chan HotplugEvent)
+	w := &HotplugWatcher{Events: c, done: make(chan struct{})}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer close(c)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+			curr, err := hotplugSnapshot()
+			if err != nil {
+				if !w.send(c, HotplugEvent{Err: err}) {
+					return
+				}
+				continue
+			}
+			for _, ev := range hotplugDiff(last, curr) {
+				if !w.send(c, ev) {
+					return
+				}
+			}
+			last = curr
+		}
+	}()
+	return w, nil
+}
+
+// hotplugSnapshot returns the currently present hotplug devices keyed by
+// class and name.
+func hotplugSnapshot() (map[string]DeviceInfo, error) {
+	snap := make(map[string]DeviceInfo)
+	for _, c := range hotplugClasses {
+		devices, err := scanClass(c.class, filepath.Join(prefix, c.path), c.prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range devices {
+			snap[d.Class+"/"+d.Name] = d
+		}
+	}
+	return snap, nil
+}
+
+// hotplugDiff returns the events required to change from the last to the
+// curr snapshot. Removals are reported before additions, and within each
+// action events are ordered as they would be by Inventory.
+func hotplugDiff(last, curr map[string]DeviceInfo) []HotplugEvent {
+	var removed, added []DeviceInfo
+	for k, d := range last {
+		if _, ok := curr[k]; !ok {
+			removed = append(removed, d)
+		}
+	}
+	for k, d := range curr {
+		if _, ok := last[k]; !ok {
+			added = append(added, d)
+		}
+	}
+	sortInfo(removed)
+	sortInfo(added)
+
+	var events []HotplugEvent
+	for _, d := range removed {
+		events = append(events, HotplugEvent{Action: DeviceRemoved, Class: d.Class, Name: d.Name, Address: d.Address, Driver: d.Driver})
+	}
+	for _, d := range added {
+		events = append(events, HotplugEvent{Action: DeviceAdded, Class: d.Class, Name: d.Name, Address: d.Address, Driver: d.Driver})
+	}
+	return events
+}
+
+// sortInfo sorts devices by class in inventory order and then by id.
+func sortInfo(devices []DeviceInfo) {
+	rank := make(map[string]int, len(inventoryClasses))
+	for i, c := range inventoryClasses {
+		rank[c.class] = i
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Class != devices[j].Class {
+			return rank[devices[i].Class] < rank[devices[j].Class]
+		}
+		return devices[i].ID < devices[j].ID
+	})
+}
+
+// ueventEvent returns a HotplugEvent for a kernel uevent message and whether
+// the message describes a change in a hotplug device class. The message is a
+// sequence of NUL-terminated strings beginning with an action@devpath header
+// followed by KEY=VALUE pairs.
+func ueventEvent(msg []byte) (HotplugEvent, bool) {
+	fields := strings.Split(strings.TrimRight(string(msg), "\x00"), "\x00")
+	if len(fields) < 2 || !strings.Contains(fields[0], "@") {
+		return HotplugEvent{}, false
+	}
+	env := make(map[string]string)
+	for _, f := range fields[1:] {
+		i := strings.Index(f, "=")
+		if i < 0 {
+			continue
+		}
+		env[f[:i]] = f[i+1:]
+	}
+
+	var ev HotplugEvent
+	switch env["ACTION"] {
+	case "add":
+		ev.Action = DeviceAdded
+	case "remove":
+		ev.Action = DeviceRemoved
+	default:
+		return HotplugEvent{}, false
+	}
+	ev.Name = filepath.Base(env["DEVPATH"])
+	for _, c := range hotplugClasses {
+		if filepath.Base(c.path) == env["SUBSYSTEM"] && strings.HasPrefix(ev.Name, c.prefix) {
+			ev.Class = c.class
+			break
+		}
+	}
+	if ev.Class == "" {
+		return HotplugEvent{}, false
+	}
+	ev.Address = env["LEGO_ADDRESS"]
+	ev.Driver = env["LEGO_DRIVER_NAME"]
+	return ev, true
+}
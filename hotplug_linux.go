@@ -0,0 +1,75 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build linux
+
+package ev3dev
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// netlinkPollTimeout is the poll timeout in milliseconds used to
+// allow a netlink HotplugWatcher to notice it has been closed.
+const netlinkPollTimeout = 100
+
+func newNetlinkWatcher() (*HotplugWatcher, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("ev3dev: failed to open uevent socket: %v", err)
+	}
+	err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: 1})
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("ev3dev: failed to bind uevent socket: %v", err)
+	}
+
+	c := make(chan HotplugEvent)
+	w := &HotplugWatcher{
+		Events:  c,
+		done:    make(chan struct{}),
+		closeFn: func() error { return unix.Close(fd) },
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer close(c)
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		buf := make([]byte, 8192)
+		for {
+			select {
+			case <-w.done:
+				return
+			default:
+			}
+			n, err := unix.Poll(fds, netlinkPollTimeout)
+			if err == unix.EINTR || n == 0 {
+				continue
+			}
+			if err != nil {
+				if !w.send(c, HotplugEvent{Err: err}) {
+					return
+				}
+				continue
+			}
+			n, _, err = unix.Recvfrom(fd, buf, 0)
+			if err != nil {
+				if !w.send(c, HotplugEvent{Err: err}) {
+					return
+				}
+				continue
+			}
+			ev, ok := ueventEvent(buf[:n])
+			if !ok {
+				continue
+			}
+			if !w.send(c, ev) {
+				return
+			}
+		}
+	}()
+	return w, nil
+}
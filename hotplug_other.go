@@ -0,0 +1,13 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !linux
+
+package ev3dev
+
+import "errors"
+
+func newNetlinkWatcher() (*HotplugWatcher, error) {
+	return nil, errors.New("ev3dev: netlink uevents need GOOS=linux")
+}
@@ -0,0 +1,214 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// These are the device class names used by DeviceInfo.
+const (
+	LegoPortClass       = "lego-port"
+	SensorClass         = "lego-sensor"
+	TachoMotorClass     = "tacho-motor"
+	LinearActuatorClass = "linear-actuator"
+	ServoMotorClass     = "servo-motor"
+	DCMotorClass        = "dc-motor"
+	LEDClass            = "leds"
+	PowerSupplyClass    = "power_supply"
+)
+
+// DeviceInfo is a description of a device found by Inventory.
+type DeviceInfo struct {
+	// Class is the device class of the device.
+	Class string
+
+	// Name is the sysfs directory name of the
+	// device.
+	Name string
+
+	// ID is the id of the device for classes
+	// with numbered devices, and -1 otherwise.
+	ID int
+
+	// Address and Driver are the port address
+	// and driver name of the device. They are
+	// empty if the class does not provide them.
+	Address string
+	Driver  string
+
+	// Modes and Commands are the available modes
+	// and commands for the device. They are nil
+	// if the class does not provide them.
+	Modes    []string
+	Commands []string
+}
+
+// inventoryClasses lists the scanned device classes in the order they
+// are reported by Inventory.
+var inventoryClasses = []struct {
+	class  string
+	path   string
+	prefix string
+}{
+	{class: LegoPortClass, path: LegoPortPath, prefix: portPrefix},
+	{class: SensorClass, path: SensorPath, prefix: sensorPrefix},
+	{class: TachoMotorClass, path: TachoMotorPath, prefix: motorPrefix},
+	{class: LinearActuatorClass, path: TachoMotorPath, prefix: linearPrefix},
+	{class: ServoMotorClass, path: ServoMotorPath, prefix: motorPrefix},
+	{class: DCMotorClass, path: DCMotorPath, prefix: motorPrefix},
+	{class: LEDClass, path: LEDPath},
+	{class: PowerSupplyClass, path: PowerSupplyPath},
+}
+
+// Inventory returns a description of every device in the lego-port,
+// lego-sensor, tacho-motor, servo-motor, dc-motor, LED and power supply
+// sysfs directories. Devices are returned grouped by class and ordered
+// by id, or by name for LEDs and power supplies. A class directory that
+// does not exist is ignored.
+func Inventory() ([]DeviceInfo, error) {
+	var inv []DeviceInfo
+	for _, c := range inventoryClasses {
+		devices, err := scanClass(c.class, filepath.Join(prefix, c.path), c.prefix)
+		if err != nil {
+			return nil, err
+		}
+		inv = append(inv, devices...)
+	}
+	return inv, nil
+}
+
+// scanClass returns a description of every device in the class directory at
+// path with names having the given prefix. If prefix is empty, all devices
+// are described by name only. A class directory that does not exist is not
+// an error.
+func scanClass(class, path, prefix string) ([]DeviceInfo, error) {
+	names, err := devicesIn(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(wrapped("ev3dev: could not get devices for %s: %w"), path, err)
+	}
+
+	var inv []DeviceInfo
+	if prefix == "" {
+		sort.Strings(names)
+		for _, n := range names {
+			inv = append(inv, DeviceInfo{Class: class, Name: n, ID: -1})
+		}
+		return inv, nil
+	}
+
+	devices, err := sortedDevices(names, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		info := DeviceInfo{Class: class, Name: d.name, ID: d.id}
+		info.Address, err = probeString(path, d.name, address)
+		if err != nil {
+			return nil, err
+		}
+		info.Driver, err = probeString(path, d.name, driverName)
+		if err != nil {
+			return nil, err
+		}
+		m, err := probeString(path, d.name, modes)
+		if err != nil {
+			return nil, err
+		}
+		info.Modes = fieldsOf(m)
+		comms, err := probeString(path, d.name, commands)
+		if err != nil {
+			return nil, err
+		}
+		info.Commands = fieldsOf(comms)
+		inv = append(inv, info)
+	}
+	return inv, nil
+}
+
+// probeString returns the chomped value of the named device attribute. If
+// the attribute does not exist, the empty string and a nil error are returned.
+func probeString(path, name, attr string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(path, name, attr))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf(wrapped("ev3dev: failed to read %s %s: %w"), name, attr, err)
+	}
+	if len(b) == 0 {
+		return "", nil
+	}
+	return string(chomp(b)), nil
+}
+
+// fieldsOf returns the space separated fields of s, or nil if there are none.
+func fieldsOf(s string) []string {
+	f := strings.Fields(s)
+	if len(f) == 0 {
+		return nil
+	}
+	return f
+}
+
+// Open returns a handle for the device described by the receiver. The concrete
+// type of the returned Device is *LegoPort, *Sensor, *TachoMotor, *LinearActuator,
+// *ServoMotor or *DCMotor depending on the device class. The usual device registry
+// rules apply to the returned handle.
+//
+// LEDs and power supplies are not opened by Open; handles for these classes are
+// obtained by using the Name field, as LED{Name: name} and PowerSupply(name).
+func (i DeviceInfo) Open() (Device, error) {
+	switch i.Class {
+	case LegoPortClass:
+		p, err := LegoPortFor(i.Address, i.Driver)
+		if p == nil {
+			return nil, err
+		}
+		return p, err
+	case SensorClass:
+		s, err := SensorFor(i.Address, i.Driver)
+		if s == nil {
+			return nil, err
+		}
+		return s, err
+	case TachoMotorClass:
+		m, err := TachoMotorFor(i.Address, i.Driver)
+		if m == nil {
+			return nil, err
+		}
+		return m, err
+	case LinearActuatorClass:
+		m, err := LinearActuatorFor(i.Address, i.Driver)
+		if m == nil {
+			return nil, err
+		}
+		return m, err
+	case ServoMotorClass:
+		m, err := ServoMotorFor(i.Address, i.Driver)
+		if m == nil {
+			return nil, err
+		}
+		return m, err
+	case DCMotorClass:
+		m, err := DCMotorFor(i.Address, i.Driver)
+		if m == nil {
+			return nil, err
+		}
+		return m, err
+	case LEDClass, PowerSupplyClass:
+		return nil, fmt.Errorf("ev3dev: cannot open %s device %s as a Device", i.Class, i.Name)
+	default:
+		return nil, fmt.Errorf("ev3dev: unknown device class %q", i.Class)
+	}
+}
@@ -24,6 +24,12 @@ type LED struct {
 	err error
 }
 
+// LEDName is an LED name that can be used as the Name of an LED.
+type LEDName string
+
+// String satisfies the fmt.Stringer interface.
+func (n LEDName) String() string { return string(n) }
+
 // ledDevice is used to fake a Device. The Type method do not
 // have meaningful semantics.
 type ledDevice struct {
@@ -58,6 +64,9 @@ func (l *LED) Brightness() (int, error) {
 // SetBrightness sets the brightness of the LED.
 func (l *LED) SetBrightness(bright int) *LED {
 	if l.err != nil {
+		if diagnose {
+			l.err = skipped(l.err, bright)
+		}
 		return l
 	}
 	max, err := l.MaxBrightness()
@@ -94,6 +103,9 @@ func (l *LED) Trigger() (current string, available []string, err error) {
 // SetTrigger sets the trigger for the LED.
 func (l *LED) SetTrigger(trig string) *LED {
 	if l.err != nil {
+		if diagnose {
+			l.err = skipped(l.err, trig)
+		}
 		return l
 	}
 	_, avail, err := l.Trigger()
@@ -124,12 +136,19 @@ func (l *LED) DelayOff() (time.Duration, error) {
 // SetDelayOff sets the duration for which the LED is off when using the timer trigger.
 func (l *LED) SetDelayOff(d time.Duration) *LED {
 	if l.err != nil {
+		if diagnose {
+			l.err = skipped(l.err, d)
+		}
 		return l
 	}
 	if d < 0 {
 		l.err = newNegativeDurationError(ledDevice{l}, delayOff, d)
 		return l
 	}
+	if maxMillis < d {
+		l.err = newDurationOutOfRangeError(ledDevice{l}, delayOff, d, 0, maxMillis)
+		return l
+	}
 	l.err = setAttributeOf(ledDevice{l}, delayOff, strconv.Itoa(int(d/time.Millisecond)))
 	return l
 }
@@ -142,12 +161,19 @@ func (l *LED) DelayOn() (time.Duration, error) {
 // SetDelayOn sets the duration for which the LED is on when using the timer trigger.
 func (l *LED) SetDelayOn(d time.Duration) *LED {
 	if l.err != nil {
+		if diagnose {
+			l.err = skipped(l.err, d)
+		}
 		return l
 	}
 	if d < 0 {
 		l.err = newNegativeDurationError(ledDevice{l}, delayOn, d)
 		return l
 	}
+	if maxMillis < d {
+		l.err = newDurationOutOfRangeError(ledDevice{l}, delayOn, d, 0, maxMillis)
+		return l
+	}
 	l.err = setAttributeOf(ledDevice{l}, delayOn, strconv.Itoa(int(d/time.Millisecond)))
 	return l
 }
@@ -136,6 +136,9 @@ func (p *LegoPort) Mode() string {
 // SetMode sets the mode of the LegoPort.
 func (p *LegoPort) SetMode(m string) *LegoPort {
 	if p.err != nil {
+		if diagnose {
+			p.err = skipped(p.err, m)
+		}
 		return p
 	}
 	ok := false
@@ -147,9 +150,12 @@ func (p *LegoPort) SetMode(m string) *LegoPort {
 	}
 	if !ok {
 		p.err = newInvalidValueError(p, mode, "", m, p.Modes())
+		if logger != nil {
+			logOp(p, "mode", LogInfo, mode, m, p.err)
+		}
 		return p
 	}
-	p.err = setAttributeOf(p, mode, m)
+	p.err = setLoggedAttributeOf(p, "mode", LogInfo, mode, m)
 	if p.err == nil {
 		p.mode, p.err = stringFrom(attributeOf(p, mode))
 	}
@@ -159,6 +165,9 @@ func (p *LegoPort) SetMode(m string) *LegoPort {
 // SetDevice sets the device of the LegoPort.
 func (p *LegoPort) SetDevice(d string) *LegoPort {
 	if p.err != nil {
+		if diagnose {
+			p.err = skipped(p.err, d)
+		}
 		return p
 	}
 	p.err = setAttributeOf(p, setDevice, d)
@@ -22,6 +22,10 @@ type LinearActuator struct {
 	countPerMeter, fullTravelCount, maxSpeed int
 	commands, stopActions                    []string
 
+	// tx holds the writes staged
+	// by a Transaction.
+	tx *transaction
+
 	err error
 }
 
@@ -139,6 +143,9 @@ func (m *LinearActuator) Commands() []string {
 // Command issues a command to the LinearActuator.
 func (m *LinearActuator) Command(comm string) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, comm)
+		}
 		return m
 	}
 	ok := false
@@ -150,9 +157,12 @@ func (m *LinearActuator) Command(comm string) *LinearActuator {
 	}
 	if !ok {
 		m.err = newInvalidValueError(m, command, "", comm, m.Commands())
+		if logger != nil {
+			logOp(m, "command", LogInfo, command, comm, m.err)
+		}
 		return m
 	}
-	m.err = setAttributeOf(m, command, comm)
+	m.err = setLoggedAttributeOf(m, "command", LogInfo, command, comm)
 	return m
 }
 
@@ -179,6 +189,9 @@ func (m *LinearActuator) DutyCycleSetpoint() (int, error) {
 // SetDutyCycleSetpoint sets the duty cycle setpoint value for the LinearActuator
 func (m *LinearActuator) SetDutyCycleSetpoint(sp int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < -100 || 100 < sp {
@@ -198,6 +211,9 @@ func (m *LinearActuator) Polarity() (Polarity, error) {
 // SetPolarity sets the polarity of the LinearActuator
 func (m *LinearActuator) SetPolarity(p Polarity) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, p)
+		}
 		return m
 	}
 	if p != Normal && p != Inversed {
@@ -216,6 +232,9 @@ func (m *LinearActuator) Position() (int, error) {
 // SetPosition sets the position value for the LinearActuator.
 func (m *LinearActuator) SetPosition(pos int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, pos)
+		}
 		return m
 	}
 	if pos != int(int32(pos)) {
@@ -234,6 +253,9 @@ func (m *LinearActuator) HoldPIDKd() (int, error) {
 // SetHoldPIDKd sets the derivative constant for the position PID for the LinearActuator.
 func (m *LinearActuator) SetHoldPIDKd(k int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, holdPIDkd, strconv.Itoa(k))
@@ -248,6 +270,9 @@ func (m *LinearActuator) HoldPIDKi() (int, error) {
 // SetHoldPIDKi sets the integral constant for the position PID for the LinearActuator.
 func (m *LinearActuator) SetHoldPIDKi(k int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, holdPIDki, strconv.Itoa(k))
@@ -262,6 +287,9 @@ func (m *LinearActuator) HoldPIDKp() (int, error) {
 // SetHoldPIDKp sets the proportional constant for the position PID for the LinearActuator.
 func (m *LinearActuator) SetHoldPIDKp(k int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, holdPIDkp, strconv.Itoa(k))
@@ -281,6 +309,9 @@ func (m *LinearActuator) PositionSetpoint() (int, error) {
 // SetPositionSetpoint sets the position setpoint value for the LinearActuator.
 func (m *LinearActuator) SetPositionSetpoint(sp int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp != int(int32(sp)) {
@@ -304,6 +335,9 @@ func (m *LinearActuator) SpeedSetpoint() (int, error) {
 // SetSpeedSetpoint sets the speed setpoint value for the LinearActuator.
 func (m *LinearActuator) SetSpeedSetpoint(sp int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedSetpoint, strconv.Itoa(sp))
@@ -318,12 +352,19 @@ func (m *LinearActuator) RampUpSetpoint() (time.Duration, error) {
 // SetRampUpSetpoint sets the ramp up setpoint value for the LinearActuator.
 func (m *LinearActuator) SetRampUpSetpoint(sp time.Duration) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, rampUpSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, rampUpSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, rampUpSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
@@ -336,12 +377,19 @@ func (m *LinearActuator) RampDownSetpoint() (time.Duration, error) {
 // SetRampDownSetpoint sets the ramp down setpoint value for the LinearActuator.
 func (m *LinearActuator) SetRampDownSetpoint(sp time.Duration) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, rampDownSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, rampDownSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, rampDownSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
@@ -354,6 +402,9 @@ func (m *LinearActuator) SpeedPIDKd() (int, error) {
 // SetSpeedPIDKd sets the derivative constant for the speed regulation PID for the LinearActuator.
 func (m *LinearActuator) SetSpeedPIDKd(sp int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedPIDkd, strconv.Itoa(sp))
@@ -368,6 +419,9 @@ func (m *LinearActuator) SpeedPIDKi() (int, error) {
 // SetSpeedPIDKi sets the integral constant for the speed regulation PID for the LinearActuator.
 func (m *LinearActuator) SetSpeedPIDKi(sp int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedPIDki, strconv.Itoa(sp))
@@ -382,6 +436,9 @@ func (m *LinearActuator) SpeedPIDKp() (int, error) {
 // SetSpeedPIDKp sets the proportional constant for the speed regulation PID for the LinearActuator.
 func (m *LinearActuator) SetSpeedPIDKp(sp int) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedPIDkp, strconv.Itoa(sp))
@@ -406,6 +463,9 @@ func (m *LinearActuator) StopAction() (string, error) {
 // issued to the LinearActuator.
 func (m *LinearActuator) SetStopAction(action string) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, action)
+		}
 		return m
 	}
 	ok := false
@@ -444,12 +504,19 @@ func (m *LinearActuator) TimeSetpoint() (time.Duration, error) {
 // SetTimeSetpoint sets the time setpoint value for the LinearActuator.
 func (m *LinearActuator) SetTimeSetpoint(sp time.Duration) *LinearActuator {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, timeSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, timeSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, timeSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
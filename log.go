@@ -0,0 +1,116 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import "strconv"
+
+// LogLevel is the severity of a logged device operation. The levels have
+// the values of the corresponding log/slog levels.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = -4
+	LogInfo  LogLevel = 0
+	LogWarn  LogLevel = 4
+	LogError LogLevel = 8
+
+	// LogOff is a minimum level that
+	// disables logging.
+	LogOff LogLevel = 1<<31 - 1
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	case LogOff:
+		return "OFF"
+	default:
+		return "LogLevel(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// Logger receives structured records of device operations. The args are
+// alternating keys and values, as for the log/slog package, so a slog.Logger
+// can be used with an adapter:
+//
+//	type slogger struct{ *slog.Logger }
+//
+//	func (l slogger) Log(level ev3dev.LogLevel, msg string, args ...interface{}) {
+//		l.Logger.Log(context.Background(), slog.Level(level), msg, args...)
+//	}
+type Logger interface {
+	Log(level LogLevel, msg string, args ...interface{})
+}
+
+var (
+	// logger is the device operation logger.
+	// It is nil when logging is disabled.
+	logger Logger
+
+	// minLevel is the default minimum level
+	// and classLevels holds the minimum
+	// levels set for device classes.
+	minLevel    = LogInfo
+	classLevels map[string]LogLevel
+)
+
+// SetLogger sets the logger for device operations, logging operations at or
+// above level. If l is nil, logging is disabled and no logging work is done.
+//
+// Attribute writes are logged at LogDebug with the message "write", commands
+// issued by Command methods are logged at LogInfo with the message "command"
+// and mode changes made by SetMode methods are logged at LogInfo with the
+// message "mode". Failed operations are logged at LogError. Each record has
+// the keys "class", "device", "attr" and "value", and "err" when the operation
+// failed. Each operation is logged once, when it is written to the device or
+// fails; writes staged by a Transaction are logged when they are applied.
+//
+// SetLogger is not safe for concurrent use with other functions in the package.
+func SetLogger(l Logger, level LogLevel) {
+	logger = l
+	minLevel = level
+}
+
+// SetLogLevel sets the minimum level of logged operations on devices of the
+// given class, overriding the level set by SetLogger. Operations on a class
+// with a level of LogOff are not logged.
+//
+// SetLogLevel is not safe for concurrent use with other functions in the
+// package.
+func SetLogLevel(class string, level LogLevel) {
+	if classLevels == nil {
+		classLevels = make(map[string]LogLevel)
+	}
+	classLevels[class] = level
+}
+
+// logOp logs the operation described by msg setting attr of d to value,
+// at the given level or at LogError if err is not nil. logOp must only be
+// called when logger is not nil.
+func logOp(d Device, msg string, level LogLevel, attr, value string, err error) {
+	if err != nil {
+		level = LogError
+	}
+	class := ClassOf(d)
+	min := minLevel
+	if l, ok := classLevels[class]; ok {
+		min = l
+	}
+	if level < min || min == LogOff {
+		return
+	}
+	if err != nil {
+		logger.Log(level, msg, "class", class, "device", d.String(), "attr", attr, "value", value, "err", err)
+		return
+	}
+	logger.Log(level, msg, "class", class, "device", d.String(), "attr", attr, "value", value)
+}
@@ -0,0 +1,117 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// recordLogger is a Logger that records its records.
+type recordLogger []string
+
+func (l *recordLogger) Log(level LogLevel, msg string, args ...interface{}) {
+	*l = append(*l, fmt.Sprintf("%v %s %v", level, msg, args[:8]))
+}
+
+func TestLogger(t *testing.T) {
+	motor := filepath.Join(TachoMotorPath, "motor0")
+	root, done := fakeRoot(t, map[string]string{
+		filepath.Join(motor, address):       "ev3-ports:outA\n",
+		filepath.Join(motor, driverName):    "lego-ev3-l-motor\n",
+		filepath.Join(motor, countPerRot):   "360\n",
+		filepath.Join(motor, maxSpeed):      "1050\n",
+		filepath.Join(motor, commands):      "run-forever stop reset\n",
+		filepath.Join(motor, stopActions):   "coast brake hold\n",
+		filepath.Join(motor, speedSetpoint): "0\n",
+		filepath.Join(motor, command):       "",
+	})
+	defer done()
+	defer func() {
+		SetLogger(nil, LogInfo)
+		classLevels = nil
+	}()
+
+	m, err := TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	run := func(m *TachoMotor) {
+		m.SetSpeedSetpoint(300).Command("run-forever").Command("jump").Err()
+	}
+
+	const (
+		write   = "DEBUG write [class tacho-motor device motor0 attr speed_sp value 300]"
+		command = "INFO command [class tacho-motor device motor0 attr command value run-forever]"
+		invalid = "ERROR command [class tacho-motor device motor0 attr command value jump]"
+		failed  = "ERROR command [class tacho-motor device motor0 attr command value run-forever]"
+	)
+	tests := []struct {
+		level    LogLevel
+		class    LogLevel
+		setClass bool
+		want     []string
+	}{
+		{level: LogDebug, want: []string{write, command, invalid}},
+		{level: LogInfo, want: []string{command, invalid}},
+		{level: LogError, want: []string{invalid}},
+		{level: LogOff, want: nil},
+		{level: LogInfo, class: LogDebug, setClass: true, want: []string{write, command, invalid}},
+		{level: LogDebug, class: LogOff, setClass: true, want: nil},
+	}
+	for i, test := range tests {
+		var l recordLogger
+		SetLogger(&l, test.level)
+		classLevels = nil
+		if test.setClass {
+			SetLogLevel("tacho-motor", test.class)
+		}
+		run(m)
+		if !reflect.DeepEqual([]string(l), test.want) {
+			t.Errorf("unexpected log for test %d:\ngot: %q\nwant:%q", i, l, test.want)
+		}
+	}
+
+	// Staged writes are logged when they are applied.
+	var l recordLogger
+	SetLogger(&l, LogDebug)
+	classLevels = nil
+	err = m.Transaction(func(m *TachoMotor) {
+		m.SetSpeedSetpoint(300).Command("run-forever")
+		if len(l) != 0 {
+			t.Errorf("unexpected log of staged writes: %q", l)
+		}
+	}).Err()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	want := []string{write, command}
+	if !reflect.DeepEqual([]string(l), want) {
+		t.Errorf("unexpected log for transaction:\ngot: %q\nwant:%q", l, want)
+	}
+
+	// Failed writes are logged once.
+	l = nil
+	err = os.Remove(filepath.Join(root, motor, "command"))
+	if err != nil {
+		t.Fatalf("failed to remove command attribute: %v", err)
+	}
+	err = os.Mkdir(filepath.Join(root, motor, "command"), 0755)
+	if err != nil {
+		t.Fatalf("failed to make command attribute unwritable: %v", err)
+	}
+	err = m.Command("run-forever").Err()
+	if err == nil {
+		t.Error("expected error for failed write")
+	}
+	want = []string{failed}
+	if !reflect.DeepEqual([]string(l), want) {
+		t.Errorf("unexpected log for failed write:\ngot: %q\nwant:%q", l, want)
+	}
+}
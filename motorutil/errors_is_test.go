@@ -0,0 +1,26 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build go1.13
+
+package motorutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ev3go/ev3dev"
+)
+
+func TestErrorsIsInvalidValue(t *testing.T) {
+	for _, err := range []error{
+		directionError(101),
+		speedError{side: "left", speed: 1100, max: 1050},
+		durationError(-1),
+	} {
+		if !errors.Is(err, ev3dev.ErrInvalidValue) {
+			t.Errorf("expected %v to match ErrInvalidValue", err)
+		}
+	}
+}
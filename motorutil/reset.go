@@ -9,12 +9,17 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ev3go/ev3dev"
 )
 
 // Errors is a collection of errors.
+//
+// The functions and methods in this package return an ev3dev.Errors when
+// more than one device fails, so that each failure is attributed to its
+// device. ErrorsOf converts an ev3dev.Errors to an Errors.
 type Errors []error
 
 func (e Errors) Error() string {
@@ -30,32 +35,46 @@ func (e Errors) Error() string {
 	return fmt.Sprintf("motorutil: multiple errors: %q", []error(e))
 }
 
+// ErrorsOf returns the errors held by errs without their device attribution.
+func ErrorsOf(errs ev3dev.Errors) Errors {
+	if errs == nil {
+		return nil
+	}
+	e := make(Errors, len(errs))
+	for i, err := range errs {
+		e[i] = err.Err
+	}
+	return e
+}
+
 // ResetAll resets all the connected motors in the classes tacho-motor,
 // servo-motor and dc-motor. Each motor class uses a different reset or
 // stop command. ResetAll sends "reset" to tacho-motors, "float" to
-// servo-motors and "stop" to dc-motors.
+// servo-motors and "stop" to dc-motors. If more than one device fails to
+// reset, the returned error is an ev3dev.Errors.
 func ResetAll() error {
-	paths, err := devicesIn(ev3dev.LegoPortPath)
+	ports := (*ev3dev.LegoPort)(nil).Path()
+	paths, err := devicesIn(ports)
 	if err != nil {
 		return err
 	}
-	var errors Errors
+	var errors ev3dev.Errors
 	for _, path := range paths {
-		port, err := portFor(ev3dev.LegoPortPath, path)
+		port, err := portFor(ports, path)
 		if err != nil {
-			errors = append(errors, err)
+			errors = append(errors, ev3dev.DeviceError{Err: err})
 			continue
 		}
 		p, err := ev3dev.LegoPortFor(port, "")
 		if _, ok := err.(ev3dev.DriverMismatch); err != nil && !ok {
-			errors = append(errors, err)
+			errors = append(errors, ev3dev.DeviceError{Err: err})
 			continue
 		}
 
 		// Find motors.
 		status, err := p.Status()
 		if err != nil {
-			errors = append(errors, err)
+			errors = append(errors, ev3dev.DeviceError{Device: p, Err: err})
 			continue
 		}
 		if !strings.HasSuffix(status, "motor") {
@@ -65,13 +84,13 @@ func ResetAll() error {
 		// Get the address of the motor.
 		uevent, err := p.Uevent()
 		if err != nil {
-			errors = append(errors, err)
+			errors = append(errors, ev3dev.DeviceError{Device: p, Err: err})
 			continue
 		}
 		const legoAddress = "LEGO_ADDRESS"
 		addr, ok := uevent[legoAddress]
 		if !ok {
-			errors = append(errors, fmt.Errorf("motorutil: cannot determine "+legoAddress+" for port %q", p))
+			errors = append(errors, ev3dev.DeviceError{Device: p, Err: fmt.Errorf("motorutil: cannot determine "+legoAddress+" for port %q", p)})
 			continue
 		}
 
@@ -82,44 +101,305 @@ func ResetAll() error {
 			// includes linear-actuators.
 			t, err := ev3dev.TachoMotorFor(addr, "")
 			if _, ok := err.(ev3dev.DriverMismatch); err != nil && !ok {
-				errors = append(errors, err)
+				errors = append(errors, ev3dev.DeviceError{Device: p, Err: err})
 				continue
 			}
 			err = t.Command("reset").Err()
 			if err != nil {
-				errors = append(errors, err)
+				errors = append(errors, ev3dev.DeviceError{Device: t, Err: err})
 			}
 		case "servo-motor":
 			s, err := ev3dev.ServoMotorFor(addr, "")
 			if _, ok := err.(ev3dev.DriverMismatch); err != nil && !ok {
-				errors = append(errors, err)
+				errors = append(errors, ev3dev.DeviceError{Device: p, Err: err})
 				continue
 			}
 			err = s.Command("float").Err()
 			if err != nil {
-				errors = append(errors, err)
+				errors = append(errors, ev3dev.DeviceError{Device: s, Err: err})
 			}
 		case "dc-motor":
 			d, err := ev3dev.DCMotorFor(addr, "")
 			if _, ok := err.(ev3dev.DriverMismatch); err != nil && !ok {
-				errors = append(errors, err)
+				errors = append(errors, ev3dev.DeviceError{Device: p, Err: err})
 				continue
 			}
 			err = d.Command("stop").Err()
 			if err != nil {
-				errors = append(errors, err)
+				errors = append(errors, ev3dev.DeviceError{Device: d, Err: err})
 			}
 		}
 	}
 
-	switch len(errors) {
-	case 0:
-		return nil
-	case 1:
-		return errors[0]
-	default:
-		return errors
+	return errors.Err()
+}
+
+// ResetOptions specifies the behaviour of Reset.
+type ResetOptions struct {
+	// TachoAction, ServoAction and DCAction are the
+	// commands sent to tacho-motors, servo-motors and
+	// dc-motors respectively. If an action is empty,
+	// the action used by ResetAll for the class is used.
+	TachoAction string
+	ServoAction string
+	DCAction    string
+
+	// Include and Exclude hold port addresses, or LED
+	// names for LEDs, of devices to include or exclude.
+	// If Include is not empty, only devices listed in
+	// Include are considered. Devices listed in Exclude
+	// are never acted on.
+	Include []string
+	Exclude []string
+
+	// Sensors specifies that lego-sensors should be
+	// reset to their default mode, the first mode in
+	// the sensor's list of available modes.
+	Sensors bool
+
+	// LEDs specifies that LEDs should be turned off.
+	LEDs bool
+
+	// DryRun specifies that Reset should only report
+	// the devices found and the actions that would have
+	// been taken without opening any device handles.
+	DryRun bool
+}
+
+// ResetAction is a record of an action taken or planned by Reset.
+type ResetAction struct {
+	// Class is the sysfs class of the device,
+	// "tacho-motor", "servo-motor", "dc-motor",
+	// "lego-sensor" or "leds".
+	Class string
+
+	// Device is the sysfs name of the device.
+	Device string
+
+	// Address and Driver are the port address
+	// and driver name of the device. They are
+	// empty for LEDs.
+	Address string
+	Driver  string
+
+	// Action is the action taken or planned for
+	// the device. For tacho-motors, servo-motors
+	// and dc-motors this is the command sent, for
+	// sensors it is "mode=" followed by the mode
+	// set and for LEDs it is "brightness=0".
+	Action string
+
+	// Skipped is true if the device was found but
+	// excluded by the ResetOptions.
+	Skipped bool
+
+	// Done is true if the action was successfully
+	// performed.
+	Done bool
+
+	// Err holds any error that occurred while
+	// reading the device's attributes or
+	// performing the action.
+	Err error
+}
+
+// ResetReport is a record of the devices found by Reset and the actions taken.
+type ResetReport []ResetAction
+
+// Reset resets the connected motors in the classes tacho-motor, servo-motor and
+// dc-motor, and optionally lego-sensors and LEDs, according to opts. Unlike ResetAll
+// Reset finds devices by scanning the device class directories rather than the
+// lego-port directory. If opts is nil, the default options are used, performing
+// the same actions as ResetAll.
+//
+// The returned ResetReport lists every device found, including those that were
+// skipped and those whose attributes could not be read. If any action fails, a
+// non-nil error is returned either as the single failure or as an ev3dev.Errors.
+func Reset(opts *ResetOptions) (ResetReport, error) {
+	if opts == nil {
+		opts = &ResetOptions{}
+	}
+	var (
+		report ResetReport
+		errors ev3dev.Errors
+	)
+	for _, class := range []struct {
+		name   string
+		dev    ev3dev.Device
+		action string
+		def    string
+		enable bool
+	}{
+		{name: "tacho-motor", dev: (*ev3dev.TachoMotor)(nil), action: opts.TachoAction, def: "reset", enable: true},
+		{name: "servo-motor", dev: (*ev3dev.ServoMotor)(nil), action: opts.ServoAction, def: "float", enable: true},
+		{name: "dc-motor", dev: (*ev3dev.DCMotor)(nil), action: opts.DCAction, def: "stop", enable: true},
+		{name: "lego-sensor", dev: (*ev3dev.Sensor)(nil), enable: opts.Sensors},
+	} {
+		if !class.enable {
+			continue
+		}
+		if class.action == "" {
+			class.action = class.def
+		}
+		names, err := devicesIn(class.dev.Path())
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			errors = append(errors, ev3dev.DeviceError{Err: err})
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			a := ResetAction{Class: class.name, Device: name, Action: class.action}
+			a.Address, err = portFor(class.dev.Path(), name)
+			if err == nil {
+				a.Driver, err = driverFor(class.dev.Path(), name)
+			}
+			var mode string
+			if err == nil && class.name == "lego-sensor" {
+				mode, err = defaultModeFor(class.dev.Path(), name)
+				if err == nil {
+					a.Action = "mode=" + mode
+				}
+			}
+			if err != nil {
+				a.Err = err
+				errors = append(errors, ev3dev.DeviceError{Err: err})
+				report = append(report, a)
+				continue
+			}
+			a.Skipped = !opts.includes(a.Address)
+			if a.Skipped || opts.DryRun {
+				report = append(report, a)
+				continue
+			}
+
+			var dev ev3dev.Device
+			switch class.name {
+			case "tacho-motor":
+				// Linear-actuators share the
+				// tacho-motor class directory.
+				if strings.HasPrefix(name, "linear") {
+					var l *ev3dev.LinearActuator
+					l, a.Err = ev3dev.LinearActuatorFor(a.Address, a.Driver)
+					if l != nil {
+						dev = l
+					}
+					if a.Err == nil {
+						a.Err = l.Command(a.Action).Err()
+					}
+					break
+				}
+				var t *ev3dev.TachoMotor
+				t, a.Err = ev3dev.TachoMotorFor(a.Address, a.Driver)
+				if t != nil {
+					dev = t
+				}
+				if a.Err == nil {
+					a.Err = t.Command(a.Action).Err()
+				}
+			case "servo-motor":
+				var s *ev3dev.ServoMotor
+				s, a.Err = ev3dev.ServoMotorFor(a.Address, a.Driver)
+				if s != nil {
+					dev = s
+				}
+				if a.Err == nil {
+					a.Err = s.Command(a.Action).Err()
+				}
+			case "dc-motor":
+				var d *ev3dev.DCMotor
+				d, a.Err = ev3dev.DCMotorFor(a.Address, a.Driver)
+				if d != nil {
+					dev = d
+				}
+				if a.Err == nil {
+					a.Err = d.Command(a.Action).Err()
+				}
+			case "lego-sensor":
+				var s *ev3dev.Sensor
+				s, a.Err = ev3dev.SensorFor(a.Address, a.Driver)
+				if s != nil {
+					dev = s
+				}
+				if a.Err == nil {
+					a.Err = s.SetMode(mode).Err()
+				}
+			}
+			a.Done = a.Err == nil
+			if a.Err != nil {
+				errors = append(errors, ev3dev.DeviceError{Device: dev, Err: a.Err})
+			}
+			report = append(report, a)
+		}
+	}
+
+	if opts.LEDs {
+		var led ev3dev.LED
+		names, err := devicesIn(led.Path())
+		if err != nil && !os.IsNotExist(err) {
+			errors = append(errors, ev3dev.DeviceError{Err: err})
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			a := ResetAction{Class: "leds", Device: name, Action: "brightness=0"}
+			a.Skipped = !opts.includes(name)
+			if !a.Skipped && !opts.DryRun {
+				a.Err = (&ev3dev.LED{Name: ev3dev.LEDName(name)}).SetBrightness(0).Err()
+				a.Done = a.Err == nil
+				if a.Err != nil {
+					// LEDs are not ev3dev.Devices, so the
+					// failure is not attributed.
+					errors = append(errors, ev3dev.DeviceError{Err: a.Err})
+				}
+			}
+			report = append(report, a)
+		}
+	}
+
+	return report, errors.Err()
+}
+
+// includes returns whether the device with the given port address
+// or LED name should be acted on according to the receiver.
+func (o *ResetOptions) includes(addr string) bool {
+	for _, e := range o.Exclude {
+		if e == addr {
+			return false
+		}
+	}
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, i := range o.Include {
+		if i == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func driverFor(path, base string) (string, error) {
+	path = filepath.Join(path, base, "driver_name")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("motorutil: failed to read driver name: %v", err)
+	}
+	return string(chomp(b)), nil
+}
+
+func defaultModeFor(path, base string) (string, error) {
+	path = filepath.Join(path, base, "modes")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("motorutil: failed to read modes: %v", err)
+	}
+	modes := strings.Fields(string(b))
+	if len(modes) == 0 {
+		return "", fmt.Errorf("motorutil: no modes available for %s", base)
 	}
+	return modes[0], nil
 }
 
 func devicesIn(path string) ([]string, error) {
@@ -141,7 +421,7 @@ func portFor(path, base string) (string, error) {
 }
 
 func chomp(b []byte) []byte {
-	if b[len(b)-1] == '\n' {
+	if len(b) != 0 && b[len(b)-1] == '\n' {
 		return b[:len(b)-1]
 	}
 	return b
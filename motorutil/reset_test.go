@@ -0,0 +1,215 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package motorutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ev3go/ev3dev"
+	"github.com/ev3go/ev3dev/ev3devtest"
+)
+
+var includesTests = []struct {
+	opts ResetOptions
+	addr string
+	want bool
+}{
+	{opts: ResetOptions{}, addr: "ev3-ports:outA", want: true},
+	{opts: ResetOptions{Include: []string{"ev3-ports:outA"}}, addr: "ev3-ports:outA", want: true},
+	{opts: ResetOptions{Include: []string{"ev3-ports:outA"}}, addr: "ev3-ports:outB", want: false},
+	{opts: ResetOptions{Exclude: []string{"ev3-ports:outA"}}, addr: "ev3-ports:outA", want: false},
+	{opts: ResetOptions{Exclude: []string{"ev3-ports:outA"}}, addr: "ev3-ports:outB", want: true},
+	{
+		opts: ResetOptions{Include: []string{"ev3-ports:outA"}, Exclude: []string{"ev3-ports:outA"}},
+		addr: "ev3-ports:outA", want: false,
+	},
+}
+
+func TestResetOptionsIncludes(t *testing.T) {
+	for _, test := range includesTests {
+		got := test.opts.includes(test.addr)
+		if got != test.want {
+			t.Errorf("unexpected inclusion of %q for include=%q exclude=%q: got:%t want:%t",
+				test.addr, test.opts.Include, test.opts.Exclude, got, test.want)
+		}
+	}
+}
+
+func TestResetAll(t *testing.T) {
+	b := ev3devtest.NewBrick()
+	b.AddPort("ev3-ports:outA", "legoev3-output-port", "tacho-motor", "dc-motor")
+	b.AddPort("ev3-ports:outB", "legoev3-output-port", "dc-motor", "tacho-motor")
+	b.AddPort("ev3-ports:outC", "legoev3-output-port", "auto", "tacho-motor")
+	tacho := b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor")
+	dc := b.AddDCMotor("ev3-ports:outB", "rcx-motor")
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+
+	err = ResetAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tacho.Attr("command"); got != "reset" {
+		t.Errorf("unexpected tacho-motor command: got:%q want:%q", got, "reset")
+	}
+	if got := dc.Attr("command"); got != "stop" {
+		t.Errorf("unexpected dc-motor command: got:%q want:%q", got, "stop")
+	}
+}
+
+func TestReset(t *testing.T) {
+	b := ev3devtest.NewEV3()
+	tacho := b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor")
+	excluded := b.AddTachoMotor("ev3-ports:outB", "lego-ev3-m-motor")
+	dc := b.AddDCMotor("ev3-ports:outC", "rcx-motor")
+	servo := b.AddServoMotor("ev3-ports:in1:i2c88:sv1", "ms-8ch-servo")
+	sensor := b.AddSensor("ev3-ports:in2", "lego-ev3-color", "COL-REFLECT", "COL-COLOR")
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+	err = sensor.SetAttr("mode", "COL-COLOR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	led := b.Device(ev3dev.LEDClass, "led0:red:brick-status")
+	err = led.SetAttr("brightness", "255")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts := &ResetOptions{
+		Exclude: []string{"ev3-ports:outB", "led1:red:brick-status", "led1:green:brick-status"},
+		Sensors: true,
+		LEDs:    true,
+		DryRun:  true,
+	}
+	want := ResetReport{
+		{Class: "tacho-motor", Device: "motor0", Address: "ev3-ports:outA", Driver: "lego-ev3-l-motor", Action: "reset"},
+		{Class: "tacho-motor", Device: "motor1", Address: "ev3-ports:outB", Driver: "lego-ev3-m-motor", Action: "reset", Skipped: true},
+		{Class: "servo-motor", Device: "motor0", Address: "ev3-ports:in1:i2c88:sv1", Driver: "ms-8ch-servo", Action: "float"},
+		{Class: "dc-motor", Device: "motor0", Address: "ev3-ports:outC", Driver: "rcx-motor", Action: "stop"},
+		{Class: "lego-sensor", Device: "sensor0", Address: "ev3-ports:in2", Driver: "lego-ev3-color", Action: "mode=COL-REFLECT"},
+		{Class: "leds", Device: "led0:green:brick-status", Action: "brightness=0"},
+		{Class: "leds", Device: "led0:red:brick-status", Action: "brightness=0"},
+		{Class: "leds", Device: "led1:green:brick-status", Action: "brightness=0", Skipped: true},
+		{Class: "leds", Device: "led1:red:brick-status", Action: "brightness=0", Skipped: true},
+	}
+
+	// A dry run reports without acting.
+	report, err := Reset(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("unexpected dry run report:\ngot: %+v\nwant:%+v", report, want)
+	}
+	for _, d := range []*ev3devtest.Device{tacho, dc, servo} {
+		if got := d.Attr("command"); got != "" {
+			t.Errorf("unexpected command for dry run on %s: %q", d.Name, got)
+		}
+	}
+
+	opts.DryRun = false
+	for i := range want {
+		want[i].Done = !want[i].Skipped
+	}
+	report, err = Reset(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("unexpected report:\ngot: %+v\nwant:%+v", report, want)
+	}
+	for _, check := range []struct {
+		dev        *ev3devtest.Device
+		attr, want string
+	}{
+		{dev: tacho, attr: "command", want: "reset"},
+		{dev: excluded, attr: "command", want: ""},
+		{dev: servo, attr: "command", want: "float"},
+		{dev: dc, attr: "command", want: "stop"},
+		{dev: sensor, attr: "mode", want: "COL-REFLECT"},
+		{dev: led, attr: "brightness", want: "0"},
+	} {
+		if got := check.dev.Attr(check.attr); got != check.want {
+			t.Errorf("unexpected %s for %s: got:%q want:%q", check.attr, check.dev.Name, got, check.want)
+		}
+	}
+}
+
+func TestResetLinearAndUnreadable(t *testing.T) {
+	b := ev3devtest.NewBrick()
+	tacho := b.AddTachoMotor("ev3-ports:outA", "lego-ev3-l-motor")
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+
+	// The fake brick has no linear-actuators or broken
+	// devices, so add them to its tree directly.
+	motors := filepath.Join(b.Root(), ev3dev.TachoMotorPath)
+	for path, data := range map[string]string{
+		"linear0/address":           "ev3-ports:outB\n",
+		"linear0/driver_name":       "act-l12-ev3-50\n",
+		"linear0/count_per_m":       "2000\n",
+		"linear0/full_travel_count": "100\n",
+		"linear0/max_speed":         "24\n",
+		"linear0/commands":          "run-forever stop reset\n",
+		"linear0/stop_actions":      "coast brake hold\n",
+		"linear0/command":           "",
+		"motor8/driver_name":        "lego-ev3-l-motor\n",
+		"motor9/address":            "ev3-ports:outD\n",
+		"motor9/driver_name":        "",
+	} {
+		path = filepath.Join(motors, path)
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("failed to make directory: %v", err)
+		}
+		err = ioutil.WriteFile(path, []byte(data), 0644)
+		if err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	report, err := Reset(nil)
+	if err == nil {
+		t.Error("expected error for unreadable device")
+	}
+	var got []string
+	for _, a := range report {
+		got = append(got, fmt.Sprintf("%s %s %q done=%t err=%t", a.Device, a.Address, a.Driver, a.Done, a.Err != nil))
+	}
+	want := []string{
+		`linear0 ev3-ports:outB "act-l12-ev3-50" done=true err=false`,
+		`motor0 ev3-ports:outA "lego-ev3-l-motor" done=true err=false`,
+		`motor8  "" done=false err=true`,
+		`motor9 ev3-ports:outD "" done=false err=true`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected report:\ngot: %q\nwant:%q", got, want)
+	}
+	cmd, err := ioutil.ReadFile(filepath.Join(motors, "linear0", "command"))
+	if err != nil {
+		t.Fatalf("failed to read linear-actuator command: %v", err)
+	}
+	if string(cmd) != "reset" {
+		t.Errorf("unexpected linear-actuator command: got:%q want:%q", cmd, "reset")
+	}
+	if got := tacho.Attr("command"); got != "reset" {
+		t.Errorf("unexpected tacho-motor command: got:%q want:%q", got, "reset")
+	}
+}
@@ -5,6 +5,7 @@
 package motorutil
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -17,7 +18,8 @@ import (
 // Steering implements a paired-motor steering unit similar to an EV3-G steering block.
 //
 // Errors ocurring during steering operations are sticky. They are returned either by
-// a call to Err or Wait.
+// a call to Err or Wait. The motor speeds of every tank and arcade operation are
+// checked against the MaxSpeed of each motor before either motor is driven.
 type Steering struct {
 	// Left and Right are the left and right motors to be
 	// used by the steering module.
@@ -27,6 +29,7 @@ type Steering struct {
 	// return to a non-driving state.
 	//
 	// See ev3dev.Wait documentation for timeout behaviour.
+	// The timeout is measured by the ev3dev package clock.
 	Timeout time.Duration
 
 	err error
@@ -166,6 +169,25 @@ func (s *Steering) SteerCounts(speed, turn, counts int) *Steering {
 	// but ev3dev ignores speed_sp for run-to-*-pos.
 	leftSpeed, leftCounts, rightSpeed, rightCounts := motorRates(speed, turn, counts)
 
+	return s.runToRelPos(leftSpeed, leftCounts, rightSpeed, rightCounts)
+}
+
+// checkSpeeds returns an error if the magnitude of the left or right speed
+// is greater than the maximum speed of the corresponding motor. It is the
+// shared validation of speeds for the tank and arcade operations.
+func (s *Steering) checkSpeeds(leftSpeed, rightSpeed int) error {
+	if max := s.Left.MaxSpeed(); abs(leftSpeed) > max {
+		return speedError{side: "left", speed: leftSpeed, max: max}
+	}
+	if max := s.Right.MaxSpeed(); abs(rightSpeed) > max {
+		return speedError{side: "right", speed: rightSpeed, max: max}
+	}
+	return nil
+}
+
+// runToRelPos runs the left and right motors with the given speeds to the given
+// relative positions.
+func (s *Steering) runToRelPos(leftSpeed, leftCounts, rightSpeed, rightCounts int) *Steering {
 	s.err = s.Left.
 		SetSpeedSetpoint(leftSpeed).
 		SetPositionSetpoint(leftCounts).
@@ -226,6 +248,12 @@ func (s *Steering) SteerDuration(speed, turn int, d time.Duration) *Steering {
 
 	leftSpeed, _, rightSpeed, _ := motorRates(speed, turn, 0)
 
+	return s.runTimed(leftSpeed, rightSpeed, d)
+}
+
+// runTimed runs the left and right motors with the given speeds for the
+// duration d.
+func (s *Steering) runTimed(leftSpeed, rightSpeed int, d time.Duration) *Steering {
 	s.err = s.Left.
 		SetSpeedSetpoint(leftSpeed).
 		SetTimeSetpoint(d).
@@ -252,6 +280,167 @@ func (s *Steering) SteerDuration(speed, turn int, d time.Duration) *Steering {
 	return s
 }
 
+// runForever runs the left and right motors with the given speeds until
+// they are stopped.
+func (s *Steering) runForever(leftSpeed, rightSpeed int) *Steering {
+	s.err = s.checkSpeeds(leftSpeed, rightSpeed)
+	if s.err != nil {
+		return s
+	}
+	s.err = s.Left.SetSpeedSetpoint(leftSpeed).Err()
+	if s.err != nil {
+		return s
+	}
+	s.err = s.Right.SetSpeedSetpoint(rightSpeed).Err()
+	if s.err != nil {
+		return s
+	}
+
+	s.err = s.Left.Command("run-forever").Err()
+	if s.err != nil {
+		return s
+	}
+	s.err = s.Right.Command("run-forever").Err()
+	if s.err != nil {
+		s.Left.Command("stop").Err()
+	}
+	return s
+}
+
+// Stop issues a stop command to both motors held by the Steering. The motors
+// are stopped according to their stop action. Stop is not subject to the
+// sticky error state of the Steering or of its motors and will attempt to
+// stop both motors even if the left motor fails to stop.
+func (s *Steering) Stop() *Steering {
+	lErr := ev3dev.WriteAttr(s.Left, "command", "stop")
+	rErr := ev3dev.WriteAttr(s.Right, "command", "stop")
+	if s.err != nil {
+		return s
+	}
+	switch {
+	case lErr != nil && rErr != nil:
+		s.err = ev3dev.Errors{{Device: s.Left, Err: lErr}, {Device: s.Right, Err: rErr}}
+	case lErr != nil:
+		s.err = lErr
+	case rErr != nil:
+		s.err = rErr
+	}
+	return s
+}
+
+// TankCounts drives the left and right motors at the given speeds for the
+// given tacho counts, similar to an EV3-G move tank block. The counts value
+// applies to the faster of the two motors and the slower motor travels a
+// proportionally shorter distance so that both motors complete their moves
+// at the same time. A motor with a negative speed is driven in reverse, and
+// if counts is negative, the directions of both motors are reversed.
+//
+// See the ev3dev.SetSpeedSetPoint and ev3dev.SetPositionSetPoint documentation for
+// speed and count behaviour.
+func (s *Steering) TankCounts(left, right, counts int) *Steering {
+	if s.err != nil {
+		return s
+	}
+
+	s.err = s.checkSpeeds(left, right)
+	if s.err != nil {
+		return s
+	}
+
+	leftSpeed, leftCounts, rightSpeed, rightCounts := tankRates(left, right, counts)
+
+	return s.runToRelPos(leftSpeed, leftCounts, rightSpeed, rightCounts)
+}
+
+// TankDuration drives the left and right motors at the given speeds for the
+// given duration, d, similar to an EV3-G move tank block.
+//
+// See the ev3dev.SetSpeedSetpoint and ev3dev.SetTimeSetpoint documentation for speed
+// and duration behaviour.
+func (s *Steering) TankDuration(left, right int, d time.Duration) *Steering {
+	if s.err != nil {
+		return s
+	}
+
+	if d < 0 {
+		s.err = durationError(d)
+		return s
+	}
+	s.err = s.checkSpeeds(left, right)
+	if s.err != nil {
+		return s
+	}
+
+	return s.runTimed(left, right, d)
+}
+
+// TankForever drives the left and right motors at the given speeds until
+// a subsequent steering operation or Stop is called. Since the motors
+// continue to run, Wait will return only when the motors have been stopped
+// or the Timeout has been reached.
+//
+// See the ev3dev.SetSpeedSetpoint documentation for speed behaviour.
+func (s *Steering) TankForever(left, right int) *Steering {
+	if s.err != nil {
+		return s
+	}
+	return s.runForever(left, right)
+}
+
+// Arcade drives the motors forever according to an arcade-drive mapping of
+// throttle and turn, as would be used with a single joystick. Both throttle
+// and turn are speeds in tacho counts per second; positive turn values turn
+// to the right. The wheel speeds are calculated by ArcadeRates.
+//
+// See the TankForever documentation for stopping and waiting behaviour.
+func (s *Steering) Arcade(throttle, turn int) *Steering {
+	if s.err != nil {
+		return s
+	}
+	left, right := ArcadeRates(throttle, turn)
+	return s.runForever(left, right)
+}
+
+// ArcadeRates returns the left and right wheel speeds corresponding to the given
+// arcade-drive throttle and turn. The left speed is throttle+turn and the right
+// speed is throttle-turn, scaled so that neither wheel speed has a magnitude
+// greater than the larger of the magnitudes of throttle and turn.
+func ArcadeRates(throttle, turn int) (left, right int) {
+	left = throttle + turn
+	right = throttle - turn
+
+	max := abs(throttle)
+	if abs(turn) > max {
+		max = abs(turn)
+	}
+	sum := abs(throttle) + abs(turn)
+	if sum > max {
+		left = (left * max) / sum
+		right = (right * max) / sum
+	}
+	return left, right
+}
+
+// tankRates returns the speeds and counts for a tank move with the given left
+// and right speeds. The counts value is applied to the faster motor.
+func tankRates(left, right, counts int) (leftSpeed, leftCounts, rightSpeed, rightCounts int) {
+	fast := abs(left)
+	if abs(right) > fast {
+		fast = abs(right)
+	}
+	if fast == 0 {
+		return left, 0, right, 0
+	}
+	return left, (counts * left) / fast, right, (counts * right) / fast
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
 func motorRates(speed, turn, counts int) (leftSpeed, leftCounts, rightSpeed, rightCounts int) {
 	switch {
 	case turn == 0:
@@ -284,7 +473,7 @@ func (s *Steering) Err() error {
 
 // Wait waits for the last steering operation to complete. A non-nil error will either
 // implement the Cause method, which may be used to determine the underlying cause, or
-// be an Errors holding errors that implement the Cause method.
+// be an ev3dev.Errors holding errors that implement the Cause method.
 func (s *Steering) Wait() error {
 	if err := s.Err(); err != nil {
 		return err
@@ -317,15 +506,28 @@ func (s *Steering) Wait() error {
 	}
 	wg.Wait()
 
-	switch {
-	case errors[0] != nil && errors[1] != nil:
-		return Errors(errors[:])
-	case errors[0] != nil:
-		return errors[0]
-	case errors[1] != nil:
-		return errors[1]
+	var errs ev3dev.Errors
+	for i, device := range []*ev3dev.TachoMotor{s.Left, s.Right} {
+		if errors[i] != nil {
+			errs = append(errs, ev3dev.DeviceError{Device: device, Err: errors[i]})
+		}
 	}
-	return nil
+	return errs.Err()
+}
+
+// WaitContext waits for the last steering operation to complete or for the context
+// to be done. Unlike Wait, WaitContext waits on both motors together and does not
+// use the Steering's Timeout. If the context is done before both motors have stopped
+// running, the context's error is returned.
+func (s *Steering) WaitContext(ctx context.Context) error {
+	if err := s.Err(); err != nil {
+		return err
+	}
+	_, _, err := ev3dev.WaitDevices(ctx, true,
+		ev3dev.StateCondition{Device: s.Left, Mask: ev3dev.Running},
+		ev3dev.StateCondition{Device: s.Right, Mask: ev3dev.Running},
+	)
+	return err
 }
 
 // directionError is a ev3dev.ValidFloat64Ranger error.
@@ -341,6 +543,26 @@ func (e directionError) Range() (value, min, max int) {
 	return int(e), -100, 100
 }
 
+func (e directionError) Is(target error) bool { return target == ev3dev.ErrInvalidValue }
+
+// speedError is a ev3dev.ValidRanger error.
+type speedError struct {
+	side       string
+	speed, max int
+}
+
+var _ ev3dev.ValidRanger = speedError{}
+
+func (e speedError) Error() string {
+	return fmt.Sprintf("motorutil: invalid %s motor speed: %d (must be within -%d to %d)", e.side, e.speed, e.max, e.max)
+}
+
+func (e speedError) Range() (value, min, max int) {
+	return e.speed, -e.max, e.max
+}
+
+func (e speedError) Is(target error) bool { return target == ev3dev.ErrInvalidValue }
+
 // durationError is a ev3dev.ValidDurationRanger error.
 type durationError time.Duration
 
@@ -354,6 +576,8 @@ func (e durationError) DurationRange() (value, min, max time.Duration) {
 	return time.Duration(e), 0, math.MaxInt64
 }
 
+func (e durationError) Is(target error) bool { return target == ev3dev.ErrInvalidValue }
+
 // waitError is a Causer error.
 type waitError struct {
 	side  string
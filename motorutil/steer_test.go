@@ -7,6 +7,10 @@ package motorutil
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+	"github.com/ev3go/ev3dev/ev3devtest"
 )
 
 var motorRatesTests = []struct {
@@ -147,3 +151,203 @@ func TestIntersect(t *testing.T) {
 		}
 	}
 }
+
+var tankRatesTests = []struct {
+	left, right, counts int
+
+	wantLeftSpeed, wantLeftCounts   int
+	wantRightSpeed, wantRightCounts int
+}{
+	{
+		left: 100, right: 100, counts: 10,
+		wantLeftSpeed: 100, wantLeftCounts: 10,
+		wantRightSpeed: 100, wantRightCounts: 10,
+	},
+	{
+		left: 50, right: 100, counts: 10,
+		wantLeftSpeed: 50, wantLeftCounts: 5,
+		wantRightSpeed: 100, wantRightCounts: 10,
+	},
+	{
+		left: 100, right: 50, counts: 10,
+		wantLeftSpeed: 100, wantLeftCounts: 10,
+		wantRightSpeed: 50, wantRightCounts: 5,
+	},
+	{
+		left: -100, right: 50, counts: 10,
+		wantLeftSpeed: -100, wantLeftCounts: -10,
+		wantRightSpeed: 50, wantRightCounts: 5,
+	},
+	{
+		left: 100, right: -100, counts: -10,
+		wantLeftSpeed: 100, wantLeftCounts: -10,
+		wantRightSpeed: -100, wantRightCounts: 10,
+	},
+	{
+		left: 0, right: 100, counts: 10,
+		wantLeftSpeed: 0, wantLeftCounts: 0,
+		wantRightSpeed: 100, wantRightCounts: 10,
+	},
+	{
+		left: 0, right: 0, counts: 10,
+		wantLeftSpeed: 0, wantLeftCounts: 0,
+		wantRightSpeed: 0, wantRightCounts: 0,
+	},
+}
+
+func TestTankRates(t *testing.T) {
+	for _, test := range tankRatesTests {
+		leftSpeed, leftCounts, rightSpeed, rightCounts := tankRates(test.left, test.right, test.counts)
+		if leftSpeed != test.wantLeftSpeed || leftCounts != test.wantLeftCounts ||
+			rightSpeed != test.wantRightSpeed || rightCounts != test.wantRightCounts {
+			t.Errorf("unexpected rates for left=%d right=%d counts=%d: got:%d/%d %d/%d want:%d/%d %d/%d",
+				test.left, test.right, test.counts,
+				leftSpeed, leftCounts, rightSpeed, rightCounts,
+				test.wantLeftSpeed, test.wantLeftCounts, test.wantRightSpeed, test.wantRightCounts)
+		}
+	}
+}
+
+var arcadeRatesTests = []struct {
+	throttle, turn int
+
+	wantLeft, wantRight int
+}{
+	{throttle: 0, turn: 0, wantLeft: 0, wantRight: 0},
+	{throttle: 100, turn: 0, wantLeft: 100, wantRight: 100},
+	{throttle: -100, turn: 0, wantLeft: -100, wantRight: -100},
+	{throttle: 0, turn: 100, wantLeft: 100, wantRight: -100},
+	{throttle: 0, turn: -100, wantLeft: -100, wantRight: 100},
+	{throttle: 100, turn: 100, wantLeft: 100, wantRight: 0},
+	{throttle: 100, turn: -100, wantLeft: 0, wantRight: 100},
+	{throttle: -100, turn: 100, wantLeft: 0, wantRight: -100},
+	{throttle: 100, turn: 50, wantLeft: 100, wantRight: 33},
+}
+
+func TestArcadeRates(t *testing.T) {
+	for _, test := range arcadeRatesTests {
+		left, right := ArcadeRates(test.throttle, test.turn)
+		if left != test.wantLeft || right != test.wantRight {
+			t.Errorf("unexpected rates for throttle=%d turn=%d: got:%d/%d want:%d/%d",
+				test.throttle, test.turn, left, right, test.wantLeft, test.wantRight)
+		}
+	}
+}
+
+var tankSpeedTests = []struct {
+	name string
+	op   func(s *Steering) *Steering
+
+	wantLeft, wantRight string
+	wantErr             bool
+}{
+	{name: "TankCounts", op: func(s *Steering) *Steering { return s.TankCounts(500, -1050, 360) }, wantLeft: "500", wantRight: "-1050"},
+	{name: "TankCounts", op: func(s *Steering) *Steering { return s.TankCounts(500, -1051, 360) }, wantErr: true},
+	{name: "TankDuration", op: func(s *Steering) *Steering { return s.TankDuration(1050, 500, time.Second) }, wantLeft: "1050", wantRight: "500"},
+	{name: "TankDuration", op: func(s *Steering) *Steering { return s.TankDuration(1051, 500, time.Second) }, wantErr: true},
+	{name: "TankForever", op: func(s *Steering) *Steering { return s.TankForever(0, 1000) }, wantLeft: "0", wantRight: "1000"},
+	{name: "TankForever", op: func(s *Steering) *Steering { return s.TankForever(0, 2000) }, wantErr: true},
+	{name: "Arcade", op: func(s *Steering) *Steering { return s.Arcade(-700, 0) }, wantLeft: "-700", wantRight: "-700"},
+	{name: "Arcade", op: func(s *Steering) *Steering { return s.Arcade(1100, 0) }, wantErr: true},
+
+	// Steering operations pass speeds to the motors unchecked.
+	{name: "SteerCounts", op: func(s *Steering) *Steering { return s.SteerCounts(1200, 0, 360) }, wantLeft: "1200", wantRight: "1200"},
+	{name: "SteerDuration", op: func(s *Steering) *Steering { return s.SteerDuration(1200, 0, time.Second) }, wantLeft: "1200", wantRight: "1200"},
+}
+
+func TestTankSpeeds(t *testing.T) {
+	b := ev3devtest.NewEV3()
+	left := b.AddTachoMotor("ev3-ports:outB", "lego-ev3-l-motor")
+	right := b.AddTachoMotor("ev3-ports:outC", "lego-ev3-l-motor")
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+
+	var s Steering
+	s.Left, err = ev3dev.TachoMotorFor("ev3-ports:outB", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Right, err = ev3dev.TachoMotorFor("ev3-ports:outC", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, test := range tankSpeedTests {
+		for _, d := range []*ev3devtest.Device{left, right} {
+			d.SetAttr("speed_sp", "0")
+			d.SetAttr("command", "")
+		}
+		err := test.op(&s).Err()
+		if test.wantErr {
+			r, ok := err.(ev3dev.ValidRanger)
+			if !ok {
+				t.Errorf("expected range error for %s: got:%v", test.name, err)
+				continue
+			}
+			v, min, max := r.Range()
+			if min <= v && v <= max {
+				t.Errorf("unexpected range for %s: %d in [%d,%d]", test.name, v, min, max)
+			}
+			for _, d := range []*ev3devtest.Device{left, right} {
+				if got := d.Attr("command"); got != "" {
+					t.Errorf("unexpected command after failed %s: %q", test.name, got)
+				}
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		if got := left.Attr("speed_sp"); got != test.wantLeft {
+			t.Errorf("unexpected left speed for %s: got:%s want:%s", test.name, got, test.wantLeft)
+		}
+		if got := right.Attr("speed_sp"); got != test.wantRight {
+			t.Errorf("unexpected right speed for %s: got:%s want:%s", test.name, got, test.wantRight)
+		}
+	}
+}
+
+func TestSteeringStop(t *testing.T) {
+	b := ev3devtest.NewEV3()
+	left := b.AddTachoMotor("ev3-ports:outB", "lego-ev3-l-motor")
+	right := b.AddTachoMotor("ev3-ports:outC", "lego-ev3-l-motor")
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer b.Close()
+
+	var s Steering
+	s.Left, err = ev3dev.TachoMotorFor("ev3-ports:outB", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Right, err = ev3dev.TachoMotorFor("ev3-ports:outC", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = s.TankForever(500, 500).Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Leave a sticky error in the left motor handle.
+	s.Left.SetStopAction("drift")
+
+	err = s.Stop().Err()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, d := range []*ev3devtest.Device{left, right} {
+		if got := d.Attr("command"); got != "stop" {
+			t.Errorf("unexpected command for %s: got:%q want:%q", d.Name, got, "stop")
+		}
+	}
+	if s.Left.Err() == nil {
+		t.Error("expected sticky error to be retained by left motor")
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"periph.io/x/periph/host/sysfs"
+
+	"github.com/ev3go/ev3dev"
 )
 
 const (
@@ -110,9 +112,10 @@ func (d *GPS) Close() error {
 	return err
 }
 
-// tx performs an I²C message transaction.
+// tx performs an I²C message transaction. Transactions are paced by sleeping
+// on the ev3dev package clock.
 func (d *GPS) tx(request byte) ([]byte, error) {
-	time.Sleep(200 * time.Millisecond)
+	ev3dev.CurrentClock().Sleep(200 * time.Millisecond)
 	c := dGPS_CommandLookup[request]
 	d.send[0] = request
 	for i := range &d.recv {
@@ -140,7 +143,7 @@ func (d *GPS) UTC() (time.Time, error) {
 	m := int(utc % 1e2)
 	utc /= 1e2
 	h := int(utc % 1e2)
-	year, month, day := time.Now().Date()
+	year, month, day := ev3dev.CurrentClock().Now().Date()
 	return time.Date(year, month, day, h, m, s, 0, time.UTC), nil
 }
 
@@ -0,0 +1,105 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadAttrs(t *testing.T) {
+	const (
+		addr   = "ev3-ports:outA"
+		driver = "lego-ev3-l-motor"
+	)
+	dir := filepath.Join(TachoMotorPath, "motor0")
+	_, done := fakeRoot(t, map[string]string{
+		filepath.Join(dir, address):     addr + "\n",
+		filepath.Join(dir, driverName):  driver + "\n",
+		filepath.Join(dir, countPerRot): "360\n",
+		filepath.Join(dir, maxSpeed):    "1050\n",
+		filepath.Join(dir, commands):    "run-forever stop reset\n",
+		filepath.Join(dir, stopActions): "coast brake hold\n",
+		filepath.Join(dir, position):    "-720\n",
+		filepath.Join(dir, speed):       "525\n",
+		filepath.Join(dir, dutyCycle):   "48\n",
+		filepath.Join(dir, state):       "running ramping\n",
+	})
+	defer done()
+
+	m, err := TachoMotorFor(addr, driver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, keep := range []bool{false, true} {
+		err = m.KeepFilesOpen(keep)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		before := time.Now()
+		vals, ts, err := ReadAttrs(m, position, state)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"-720", "running ramping"}; !reflect.DeepEqual(vals, want) {
+			t.Errorf("unexpected values: got:%q want:%q", vals, want)
+		}
+		if ts.Before(before) || ts.After(time.Now()) {
+			t.Errorf("unexpected time: %v", ts)
+		}
+
+		stat, err := m.Status()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		stat.Time = time.Time{}
+		want := TachoMotorStatus{Position: -720, Speed: 525, DutyCycle: 48, State: Running | Ramping}
+		if stat != want {
+			t.Errorf("unexpected status: got:%+v want:%+v", stat, want)
+		}
+	}
+
+	then := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	SetClock(fixedClock(then))
+	_, ts, err := ReadAttrs(m, position)
+	SetClock(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ts.Equal(then) {
+		t.Errorf("unexpected time with fixed clock: got:%v want:%v", ts, then)
+	}
+	if CurrentClock() != SystemClock {
+		t.Errorf("clock not restored: %T", CurrentClock())
+	}
+
+	_, _, err = ReadAttrs(m, position, "missing")
+	if err == nil {
+		t.Error("expected error for missing attribute")
+	}
+	_, err = m.Status()
+	if err != nil {
+		t.Errorf("unexpected error after failed read: %v", err)
+	}
+
+	sticky := errors.New("sticky")
+	m.err = sticky
+	_, err = m.Status()
+	if err != sticky {
+		t.Errorf("unexpected error for sticky error: got:%v want:%v", err, sticky)
+	}
+}
+
+// fixedClock is a Clock that never advances.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time                       { return time.Time(c) }
+func (c fixedClock) Sleep(time.Duration)                  {}
+func (c fixedClock) After(time.Duration) <-chan time.Time { return time.After(0) }
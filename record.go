@@ -0,0 +1,316 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+// Session operations.
+const (
+	readOp        = "read"
+	writeOp       = "write"
+	listOp        = "list"
+	buttonsOp     = "buttons"
+	buttonEventOp = "button-event"
+	toneOp        = "tone"
+)
+
+// session is a device I/O recording or replay session.
+type session interface {
+	// do performs or replays the operation op on the
+	// file at path with the given data, returning the
+	// result. When recording, fn is called to perform
+	// the operation.
+	do(op, path, data string, fn func() (string, error)) (string, error)
+
+	// replaying returns whether the session
+	// replays rather than performs operations.
+	replaying() bool
+}
+
+// sess is the active session. It is nil when
+// device I/O is neither recorded nor replayed.
+var sess session
+
+// errReplayEnd is returned by a replay session
+// when there are no more recorded button events.
+var errReplayEnd = errors.New("ev3dev: end of replayed button events")
+
+// event is a recorded device I/O operation.
+type event struct {
+	// Time is the time of completion of the
+	// operation since the start of recording.
+	Time time.Duration `json:"t"`
+
+	Op string `json:"op"`
+
+	// Path is the path of the file relative
+	// to the filesystem root set by SetRoot.
+	Path string `json:"path"`
+
+	// Data is the data written by the operation.
+	Data string `json:"data,omitempty"`
+
+	// Result and Bin hold the result of the
+	// operation, in Bin if it is not valid
+	// UTF-8.
+	Result string `json:"result,omitempty"`
+	Bin    []byte `json:"bin,omitempty"`
+
+	// Err, ErrOp and Errno describe an error
+	// returned by the operation. ErrOp and
+	// Errno are set when the error was a
+	// path error with an errno.
+	Err   string `json:"err,omitempty"`
+	ErrOp string `json:"errop,omitempty"`
+	Errno int    `json:"errno,omitempty"`
+}
+
+func (e *event) setResult(res string) {
+	if utf8.ValidString(res) {
+		e.Result = res
+	} else {
+		e.Bin = []byte(res)
+	}
+}
+
+func (e *event) result() string {
+	if e.Bin != nil {
+		return string(e.Bin)
+	}
+	return e.Result
+}
+
+func (e *event) setErr(err error) {
+	if err == nil {
+		return
+	}
+	e.Err = err.Error()
+	if pe, ok := err.(*os.PathError); ok {
+		if errno, ok := pe.Err.(syscall.Errno); ok {
+			e.ErrOp = pe.Op
+			e.Errno = int(errno)
+		}
+	}
+}
+
+func (e *event) error() error {
+	switch {
+	case e.Errno != 0:
+		return &os.PathError{Op: e.ErrOp, Path: filepath.Join(prefix, e.Path), Err: syscall.Errno(e.Errno)}
+	case e.Err != "":
+		return errors.New(e.Err)
+	}
+	return nil
+}
+
+// rel returns path relative to the filesystem root.
+func rel(path string) string {
+	if prefix == "" {
+		return path
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+// Recorder records device I/O.
+type Recorder struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	start  time.Time
+	closed bool
+	err    error
+}
+
+// Record starts recording device I/O to w and returns the Recorder. Each
+// operation is written to w as a line of JSON when it completes, with the
+// time since the start of recording measured by the package clock.
+//
+// Reads and writes of device attributes, including those made while finding
+// devices, button polls and events, and speaker tones are recorded. Wait,
+// WaitContext and WaitDevices read motor states as attributes while a
+// recording or replay is active, rather than polling the state files. I/O
+// made by Inventory, AttributeWatcher, HotplugWatcher and the sensor direct
+// attribute is not recorded.
+//
+// Record replaces any active recording or replay. Record is not safe for
+// concurrent use with other functions in the package.
+func Record(w io.Writer) *Recorder {
+	r := &Recorder{enc: json.NewEncoder(w), start: clock.Now()}
+	sess = r
+	return r
+}
+
+func (r *Recorder) do(op, path, data string, fn func() (string, error)) (string, error) {
+	res, err := fn()
+	e := event{Time: clock.Now().Sub(r.start), Op: op, Path: rel(path), Data: data}
+	e.setResult(res)
+	e.setErr(err)
+	r.mu.Lock()
+	if !r.closed && r.err == nil {
+		r.err = r.enc.Encode(e)
+	}
+	r.mu.Unlock()
+	return res, err
+}
+
+func (r *Recorder) replaying() bool { return false }
+
+// Close stops the recording and returns the first error encountered while
+// writing the log.
+func (r *Recorder) Close() error {
+	if sess == r {
+		sess = nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return r.err
+}
+
+// Replayer replays recorded device I/O.
+type Replayer struct {
+	mu     sync.Mutex
+	events map[string][]event
+	n      int
+	start  time.Time
+	err    error
+}
+
+// Replay starts replaying the device I/O recorded by a Recorder and read
+// from r, and returns the Replayer. While the replay is active, reads are
+// served from the recording and writes and tones are checked against it
+// without accessing the devices. Button events are delivered at their
+// recorded times, measured by the package clock.
+//
+// Operations are matched to the recording in order for each operation and
+// file, so the replay is not affected by the interleaving of I/O made by
+// concurrent goroutines. An operation that is not in the recording, or a
+// write that does not match the recorded data, returns an error and is
+// reported by Close.
+//
+// Replay replaces any active recording or replay. Replay is not safe for
+// concurrent use with other functions in the package.
+func Replay(r io.Reader) (*Replayer, error) {
+	p := &Replayer{events: make(map[string][]event)}
+	dec := json.NewDecoder(r)
+	for {
+		var e event
+		err := dec.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(wrapped("ev3dev: failed to read recording: %w"), err)
+		}
+		k := e.Op + " " + e.Path
+		p.events[k] = append(p.events[k], e)
+		p.n++
+	}
+	p.start = clock.Now()
+	sess = p
+	return p, nil
+}
+
+func (p *Replayer) do(op, path, data string, _ func() (string, error)) (string, error) {
+	path = rel(path)
+	k := op + " " + path
+	p.mu.Lock()
+	q := p.events[k]
+	if len(q) == 0 {
+		p.mu.Unlock()
+		if op == buttonEventOp {
+			return "", errReplayEnd
+		}
+		return "", p.fail(fmt.Errorf("ev3dev: replay: no recorded %s of %s", op, path))
+	}
+	e := q[0]
+	p.events[k] = q[1:]
+	p.n--
+	p.mu.Unlock()
+
+	if e.Data != data {
+		return "", p.fail(fmt.Errorf("ev3dev: replay: %s of %q to %s does not match recorded %q", op, data, path, e.Data))
+	}
+	if op == buttonEventOp {
+		if wait := e.Time - clock.Now().Sub(p.start); wait > 0 {
+			clock.Sleep(wait)
+		}
+	}
+	return e.result(), e.error()
+}
+
+// fail records err as the first replay error if no error has been
+// recorded, and returns it.
+func (p *Replayer) fail(err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+	return err
+}
+
+func (p *Replayer) replaying() bool { return true }
+
+// Remaining returns the number of recorded operations that have not been
+// replayed.
+func (p *Replayer) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.n
+}
+
+// Close stops the replay and returns the first error encountered during
+// the replay.
+func (p *Replayer) Close() error {
+	if sess == p {
+		sess = nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// sessionRead performs the read fn of the file at path in the active session.
+func sessionRead(path string, fn func() ([]byte, error)) ([]byte, error) {
+	res, err := sess.do(readOp, path, "", func() (string, error) {
+		b, err := fn()
+		return string(b), err
+	})
+	if res == "" {
+		return nil, err
+	}
+	return []byte(res), err
+}
+
+// sessionWrite performs the write fn of data to the file at path in the
+// active session.
+func sessionWrite(path, data string, fn func() error) error {
+	_, err := sess.do(writeOp, path, data, func() (string, error) {
+		return "", fn()
+	})
+	return err
+}
+
+// readFile reads the file at path, recording or replaying the read when
+// a session is active.
+func readFile(path string) ([]byte, error) {
+	if sess != nil {
+		return sessionRead(path, func() ([]byte, error) { return ioutil.ReadFile(path) })
+	}
+	return ioutil.ReadFile(path)
+}
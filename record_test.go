@@ -0,0 +1,169 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sessionResult is the result of running recordedProgram.
+type sessionResult struct {
+	Position int
+	State    MotorState
+	BinData  []byte
+	Missing  bool
+	Buttons  string
+}
+
+// recordedProgram is a program whose device I/O is recorded and replayed.
+func recordedProgram(t *testing.T, speed int) sessionResult {
+	var res sessionResult
+
+	m, err := TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = m.SetSpeedSetpoint(speed).Command("run-forever").Err()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return res
+	}
+	res.Position, err = m.Position()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.State, _, err = Wait(m, Running, 0, 0, false, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, _, err = attributeOf(m, "missing")
+	res.Missing = os.IsNotExist(cause(err))
+
+	s, err := SensorFor("ev3-ports:in1", "lego-ev3-touch")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.BinData, err = s.BinData()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The button device is not an event device,
+	// so polling fails.
+	var p ButtonPoller
+	_, err = p.Poll()
+	if err != nil {
+		res.Buttons = err.Error()
+	}
+	return res
+}
+
+func TestRecordReplay(t *testing.T) {
+	motor := filepath.Join(TachoMotorPath, "motor0")
+	sensor := filepath.Join(SensorPath, "sensor0")
+	root, done := fakeRoot(t, map[string]string{
+		filepath.Join(motor, address):          "ev3-ports:outA\n",
+		filepath.Join(motor, driverName):       "lego-ev3-l-motor\n",
+		filepath.Join(motor, countPerRot):      "360\n",
+		filepath.Join(motor, maxSpeed):         "1050\n",
+		filepath.Join(motor, commands):         "run-forever stop reset\n",
+		filepath.Join(motor, stopActions):      "coast brake hold\n",
+		filepath.Join(motor, speedSetpoint):    "0\n",
+		filepath.Join(motor, command):          "",
+		filepath.Join(motor, position):         "-720\n",
+		filepath.Join(motor, state):            "running\n",
+		filepath.Join(sensor, address):         "ev3-ports:in1\n",
+		filepath.Join(sensor, driverName):      "lego-ev3-touch\n",
+		filepath.Join(sensor, binData):         "\x00\xff\x10",
+		filepath.Join(sensor, firmwareVersion): "\n",
+		filepath.Join(sensor, commands):        "\n",
+		filepath.Join(sensor, modes):           "TOUCH\n",
+		filepath.Join(sensor, mode):            "TOUCH\n",
+		filepath.Join(sensor, binDataFormat):   "s8\n",
+		filepath.Join(sensor, decimals):        "0\n",
+		filepath.Join(sensor, numValues):       "1\n",
+		filepath.Join(sensor, units):           "\n",
+		ButtonPath:                             "",
+	})
+	defer done()
+
+	var buf bytes.Buffer
+	r := Record(&buf)
+	want := recordedProgram(t, 300)
+	err := r.Close()
+	if err != nil {
+		t.Fatalf("unexpected error closing recorder: %v", err)
+	}
+	if sess != nil {
+		t.Fatal("session not ended by close")
+	}
+	wantResult := sessionResult{
+		Position: -720,
+		State:    Running,
+		BinData:  []byte("\x00\xff\x10"),
+		Missing:  true,
+		Buttons:  want.Buttons,
+	}
+	if !reflect.DeepEqual(want, wantResult) {
+		t.Errorf("unexpected recorded result: got:%+v want:%+v", want, wantResult)
+	}
+	if want.Buttons == "" {
+		t.Error("expected error polling buttons")
+	}
+	log := buf.String()
+	for _, s := range []string{`"op":"list"`, `"op":"write","path":"` + filepath.Join(motor, command) + `","data":"run-forever"`, `"op":"buttons"`} {
+		if !strings.Contains(log, s) {
+			t.Errorf("recording does not contain %s", s)
+		}
+	}
+
+	// Replay against a missing device tree.
+	err = os.RemoveAll(root)
+	if err != nil {
+		t.Fatalf("failed to remove device tree: %v", err)
+	}
+
+	p, err := Replay(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := recordedProgram(t, 300)
+	if n := p.Remaining(); n != 0 {
+		t.Errorf("unexpected number of remaining operations: %d", n)
+	}
+	err = p.Close()
+	if err != nil {
+		t.Errorf("unexpected replay error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected replayed result: got:%+v want:%+v", got, want)
+	}
+
+	// Replay with a diverging write.
+	p, err = Replay(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m, err := TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = m.SetSpeedSetpoint(500).Err()
+	if err == nil {
+		t.Error("expected error for mismatched write")
+	}
+	err = p.Close()
+	if err == nil {
+		t.Error("expected replay error for mismatched write")
+	}
+	if p.Remaining() == 0 {
+		t.Error("expected remaining operations")
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"os"
+	"syscall"
+)
+
+// resilience holds the state required to rebind a resilient handle to
+// its device after the device has been disconnected and reconnected.
+type resilience struct {
+	address, driver string
+
+	// attrs and config are the configuration
+	// attributes written to the device, in
+	// order of first write, and their values.
+	attrs  []string
+	config map[string]string
+
+	// rebinding is true while the handle
+	// is being rebound.
+	rebinding bool
+}
+
+// record records a configuration attribute write.
+func (r *resilience) record(attr, data string) {
+	switch attr {
+	case command, position:
+		// Commands and positions are actions,
+		// not configuration.
+		return
+	}
+	if _, ok := r.config[attr]; !ok {
+		r.attrs = append(r.attrs, attr)
+	}
+	r.config[attr] = data
+}
+
+// resilientDevice is a Device that may be rebound to its device after
+// reconnection.
+type resilientDevice interface {
+	idSetter
+
+	// resilience returns the resilience state of
+	// the device, or nil if the device is not
+	// resilient.
+	resilience() *resilience
+
+	// rebindTo sets the device id to the given id,
+	// retaining the resilience state and any
+	// transaction being staged.
+	rebindTo(id int) error
+
+	// zero returns a nil pointer of the device's type.
+	zero() Device
+}
+
+// isDisconnected returns whether err indicates that the device directory
+// or attribute is no longer present.
+func isDisconnected(err error) bool {
+	if os.IsNotExist(err) {
+		return true
+	}
+	if pe, ok := err.(*os.PathError); ok {
+		return pe.Err == syscall.ENODEV
+	}
+	return false
+}
+
+// rebind attempts to rebind d to its device if d is a resilient device and
+// err indicates the device has been disconnected. If the device is found
+// again at the same address with the same driver, d is rebound, its cached
+// values are refreshed and its recorded configuration is rewritten. The
+// returned bool reports whether d was rebound.
+func rebind(d Device, err error) bool {
+	if !isDisconnected(err) {
+		return false
+	}
+	rd, ok := d.(resilientDevice)
+	if !ok {
+		return false
+	}
+	r := rd.resilience()
+	if r == nil || r.rebinding {
+		return false
+	}
+	r.rebinding = true
+	defer func() { r.rebinding = false }()
+
+	id, err := deviceIDFor(r.address, r.driver, rd.zero(), -1)
+	if err != nil {
+		return false
+	}
+	if id == rd.idInt() {
+		// The device was not renumbered, so
+		// the failure is not due to reconnection.
+		return false
+	}
+	err = rd.rebindTo(id)
+	if err != nil {
+		return false
+	}
+	for _, attr := range r.attrs {
+		err = writeAttributeOf(d, attr, r.config[attr])
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ResilientTachoMotorFor returns a TachoMotor for the given ev3 port name and driver
+// that will rebind to its tacho-motor if the motor is disconnected and reconnected.
+//
+// When a tacho-motor is reconnected it is given a new sysfs directory. A resilient
+// TachoMotor remembers its port address and driver, and the last value written to
+// each configuration attribute; all attributes other than command and position.
+// When an attribute operation fails because the motor's directory no longer exists,
+// the handle is rebound to the new directory for the same port address and driver,
+// its cached values are refreshed, its configuration is rewritten and the failed
+// operation is retried. If the motor has not been reconnected, the operation fails
+// as it would for a non-resilient handle.
+//
+// Otherwise, ResilientTachoMotorFor behaves as TachoMotorFor.
+func ResilientTachoMotorFor(port, driver string) (*TachoMotor, error) {
+	m, err := TachoMotorFor(port, driver)
+	if m == nil {
+		return nil, err
+	}
+	addr, _err := AddressOf(m)
+	if _err != nil {
+		return m, _err
+	}
+	m.res = &resilience{address: addr, driver: m.driver, config: make(map[string]string)}
+	return m, err
+}
+
+// IsResilient returns whether the TachoMotor was obtained with ResilientTachoMotorFor.
+func (m *TachoMotor) IsResilient() bool {
+	return m != nil && m.res != nil
+}
+
+func (m *TachoMotor) resilience() *resilience {
+	if m == nil {
+		return nil
+	}
+	return m.res
+}
+
+func (m *TachoMotor) rebindTo(id int) error {
+	old := *m
+	err := m.setID(id)
+	if err != nil {
+		*m = old
+		return err
+	}
+	m.res = old.res
+	m.tx = old.tx
+	return nil
+}
+
+func (*TachoMotor) zero() Device { return (*TachoMotor)(nil) }
@@ -0,0 +1,89 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResilientTachoMotor(t *testing.T) {
+	const (
+		addr   = "ev3-ports:outA"
+		driver = "lego-ev3-l-motor"
+	)
+	motor := func(name string) map[string]string {
+		dir := filepath.Join(TachoMotorPath, name)
+		return map[string]string{
+			filepath.Join(dir, address):       addr + "\n",
+			filepath.Join(dir, driverName):    driver + "\n",
+			filepath.Join(dir, countPerRot):   "360\n",
+			filepath.Join(dir, maxSpeed):      "1050\n",
+			filepath.Join(dir, commands):      "run-forever stop reset\n",
+			filepath.Join(dir, stopActions):   "coast brake hold\n",
+			filepath.Join(dir, speedSetpoint): "0\n",
+			filepath.Join(dir, stopAction):    "coast\n",
+			filepath.Join(dir, position):      "0\n",
+			filepath.Join(dir, command):       "\n",
+		}
+	}
+	root, done := fakeRoot(t, motor("motor0"))
+	defer done()
+
+	m, err := ResilientTachoMotorFor(addr, driver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer Release(m)
+	if !m.IsResilient() {
+		t.Fatal("expected resilient motor")
+	}
+
+	err = m.SetSpeedSetpoint(500).SetStopAction("brake").SetPosition(10).Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Simulate disconnection and reconnection.
+	err = os.RemoveAll(filepath.Join(root, TachoMotorPath, "motor0"))
+	if err != nil {
+		t.Fatalf("failed to remove motor: %v", err)
+	}
+	writeTree(t, root, motor("motor1"))
+
+	err = m.Command("run-forever").Err()
+	if err != nil {
+		t.Fatalf("unexpected error after reconnection: %v", err)
+	}
+	if got := m.String(); got != "motor1" {
+		t.Errorf("unexpected rebound motor: got:%s want:motor1", got)
+	}
+	for _, test := range []struct{ attr, want string }{
+		{attr: speedSetpoint, want: "500"},
+		{attr: stopAction, want: "brake"},
+		{attr: position, want: "0"},
+		{attr: command, want: "run-forever"},
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(root, TachoMotorPath, "motor1", test.attr))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", test.attr, err)
+		}
+		if got := string(chomp(b)); got != test.want {
+			t.Errorf("unexpected %s after reconnection: got:%q want:%q", test.attr, got, test.want)
+		}
+	}
+
+	// Simulate disconnection without reconnection.
+	err = os.RemoveAll(filepath.Join(root, TachoMotorPath, "motor1"))
+	if err != nil {
+		t.Fatalf("failed to remove motor: %v", err)
+	}
+	_, err = m.Speed()
+	if err == nil {
+		t.Error("expected error for disconnected motor")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package robot provides loading of declarative robot descriptions.
+//
+// A robot description is a JSON document declaring named motors, sensors,
+// LED groups and steering pairs. For example:
+//
+//  {
+//  	"motors": {
+//  		"left":  {"port": "ev3-ports:outB", "driver": "lego-ev3-l-motor", "stop_action": "brake"},
+//  		"right": {"port": "ev3-ports:outC", "driver": "lego-ev3-l-motor", "stop_action": "brake"},
+//  		"arm":   {"port": "ev3-ports:outA", "driver": "lego-ev3-m-motor", "polarity": "inversed"}
+//  	},
+//  	"sensors": {
+//  		"touch": {"port": "ev3-ports:in1", "driver": "lego-ev3-touch"},
+//  		"color": {"port": "ev3-ports:in3", "driver": "lego-ev3-color", "mode": "COL-REFLECT"}
+//  	},
+//  	"leds": {
+//  		"red": ["led0:red:brick-status", "led1:red:brick-status"]
+//  	},
+//  	"steering": {
+//  		"drive": {"left": "left", "right": "right", "timeout": "10s"}
+//  	}
+//  }
+//
+// Loading a description validates it and then opens a handle for each
+// declared device, checking that the device is present with the declared
+// driver and applying the declared configuration.
+package robot
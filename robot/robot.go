@@ -0,0 +1,357 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+	"github.com/ev3go/ev3dev/motorutil"
+)
+
+// Config is a robot description.
+type Config struct {
+	// Motors holds the tacho-motors
+	// of the robot keyed by name.
+	Motors map[string]MotorConfig `json:"motors"`
+
+	// Sensors holds the lego-sensors
+	// of the robot keyed by name.
+	Sensors map[string]SensorConfig `json:"sensors"`
+
+	// LEDs holds groups of LED names
+	// keyed by group name.
+	LEDs map[string][]string `json:"leds"`
+
+	// Steering holds steering pairs
+	// keyed by name.
+	Steering map[string]SteeringConfig `json:"steering"`
+}
+
+// MotorConfig is a tacho-motor description.
+type MotorConfig struct {
+	// Port and Driver are the port address and
+	// driver name of the motor. Both are required.
+	Port   string `json:"port"`
+	Driver string `json:"driver"`
+
+	// Polarity and StopAction are optional and
+	// are applied to the motor when it is opened.
+	Polarity   ev3dev.Polarity `json:"polarity"`
+	StopAction string          `json:"stop_action"`
+}
+
+// SensorConfig is a lego-sensor description.
+type SensorConfig struct {
+	// Port and Driver are the port address and
+	// driver name of the sensor. Both are required.
+	Port   string `json:"port"`
+	Driver string `json:"driver"`
+
+	// Mode is optional and is applied to the
+	// sensor when it is opened.
+	Mode string `json:"mode"`
+}
+
+// SteeringConfig is a steering pair description.
+type SteeringConfig struct {
+	// Left and Right are the names of
+	// motors declared in the Config.
+	Left  string `json:"left"`
+	Right string `json:"right"`
+
+	// Timeout is an optional time.Duration
+	// string used as the Steering Timeout.
+	Timeout string `json:"timeout"`
+}
+
+// Parse parses a JSON robot description from r and validates it.
+// Fields not described by Config are rejected.
+func Parse(r io.Reader) (*Config, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var c Config
+	err := dec.Decode(&c)
+	if err != nil {
+		return nil, fmt.Errorf("robot: failed to parse description: %v", err)
+	}
+	err = c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Load parses the JSON robot description in the named file and opens the
+// described devices.
+func Load(path string) (*Robot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("robot: failed to open description: %v", err)
+	}
+	defer f.Close()
+	c, err := Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	return c.Open()
+}
+
+// ConfigErrors is a collection of problems found in a robot description.
+type ConfigErrors []error
+
+func (e ConfigErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("robot: multiple errors: %q", []error(e))
+}
+
+// Validate checks that the description is internally consistent without
+// reference to the connected devices. If more than one problem is found,
+// the returned error is a ConfigErrors.
+func (c *Config) Validate() error {
+	var errs ConfigErrors
+	ports := make(map[string]string)
+	claim := func(kind, name, port string) {
+		if other, ok := ports[port]; ok {
+			errs = append(errs, fmt.Errorf("robot: %s %q: port %s already used by %s", kind, name, port, other))
+			return
+		}
+		ports[port] = fmt.Sprintf("%s %q", kind, name)
+	}
+
+	for _, name := range motorNames(c.Motors) {
+		m := c.Motors[name]
+		if m.Port == "" {
+			errs = append(errs, fmt.Errorf("robot: motor %q: missing port", name))
+		} else {
+			claim("motor", name, m.Port)
+		}
+		if m.Driver == "" {
+			errs = append(errs, fmt.Errorf("robot: motor %q: missing driver", name))
+		}
+		if m.Polarity != "" && m.Polarity != ev3dev.Normal && m.Polarity != ev3dev.Inversed {
+			errs = append(errs, fmt.Errorf("robot: motor %q: invalid polarity %q", name, m.Polarity))
+		}
+	}
+	for _, name := range sensorNames(c.Sensors) {
+		s := c.Sensors[name]
+		if s.Port == "" {
+			errs = append(errs, fmt.Errorf("robot: sensor %q: missing port", name))
+		} else {
+			claim("sensor", name, s.Port)
+		}
+		if s.Driver == "" {
+			errs = append(errs, fmt.Errorf("robot: sensor %q: missing driver", name))
+		}
+	}
+	for _, name := range ledGroupNames(c.LEDs) {
+		if len(c.LEDs[name]) == 0 {
+			errs = append(errs, fmt.Errorf("robot: LED group %q: no LEDs", name))
+		}
+	}
+	for _, name := range steeringNames(c.Steering) {
+		s := c.Steering[name]
+		for _, side := range []struct{ side, motor string }{{"left", s.Left}, {"right", s.Right}} {
+			if side.motor == "" {
+				errs = append(errs, fmt.Errorf("robot: steering %q: missing %s motor", name, side.side))
+				continue
+			}
+			if _, ok := c.Motors[side.motor]; !ok {
+				errs = append(errs, fmt.Errorf("robot: steering %q: %s motor %q not declared", name, side.side, side.motor))
+			}
+		}
+		if s.Left != "" && s.Left == s.Right {
+			errs = append(errs, fmt.Errorf("robot: steering %q: left and right motors are both %q", name, s.Left))
+		}
+		if s.Timeout != "" {
+			if _, err := time.ParseDuration(s.Timeout); err != nil {
+				errs = append(errs, fmt.Errorf("robot: steering %q: invalid timeout: %v", name, err))
+			}
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// Robot holds ready handles for the devices of a robot description.
+type Robot struct {
+	Motors   map[string]*ev3dev.TachoMotor
+	Sensors  map[string]*ev3dev.Sensor
+	LEDs     map[string][]*ev3dev.LED
+	Steering map[string]*motorutil.Steering
+}
+
+// Open validates the description and opens handles for all the described
+// devices, checking them against the connected devices and applying their
+// declared configuration. All devices are checked, and if any device is
+// missing, does not match its description or cannot be configured, the
+// handles that were opened are released and an error describing every
+// failure is returned. If more than one failure is found, the returned
+// error is an ev3dev.Errors. If the description is not valid, the error
+// returned by Validate is returned.
+func (c *Config) Open() (*Robot, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Robot{
+		Motors:   make(map[string]*ev3dev.TachoMotor),
+		Sensors:  make(map[string]*ev3dev.Sensor),
+		LEDs:     make(map[string][]*ev3dev.LED),
+		Steering: make(map[string]*motorutil.Steering),
+	}
+	var errs ev3dev.Errors
+	for _, name := range motorNames(c.Motors) {
+		desc := c.Motors[name]
+		m, err := ev3dev.TachoMotorFor(desc.Port, desc.Driver)
+		if m != nil {
+			r.Motors[name] = m
+		}
+		if err != nil {
+			e := ev3dev.DeviceError{Err: deviceError("motor", name, desc.Port, err)}
+			if m != nil {
+				e.Device = m
+			}
+			errs = append(errs, e)
+			continue
+		}
+		if desc.Polarity != "" {
+			m.SetPolarity(desc.Polarity)
+		}
+		if desc.StopAction != "" {
+			m.SetStopAction(desc.StopAction)
+		}
+		err = m.Err()
+		if err != nil {
+			errs = append(errs, ev3dev.DeviceError{Device: m, Err: fmt.Errorf("robot: motor %q: failed to configure: %v", name, err)})
+		}
+	}
+	for _, name := range sensorNames(c.Sensors) {
+		desc := c.Sensors[name]
+		s, err := ev3dev.SensorFor(desc.Port, desc.Driver)
+		if s != nil {
+			r.Sensors[name] = s
+		}
+		if err != nil {
+			e := ev3dev.DeviceError{Err: deviceError("sensor", name, desc.Port, err)}
+			if s != nil {
+				e.Device = s
+			}
+			errs = append(errs, e)
+			continue
+		}
+		if desc.Mode != "" {
+			err = s.SetMode(desc.Mode).Err()
+			if err != nil {
+				errs = append(errs, ev3dev.DeviceError{Device: s, Err: fmt.Errorf("robot: sensor %q: failed to configure: %v", name, err)})
+			}
+		}
+	}
+	for _, name := range ledGroupNames(c.LEDs) {
+		for _, n := range c.LEDs[name] {
+			l := &ev3dev.LED{Name: ev3dev.LEDName(n)}
+			_, err := l.MaxBrightness()
+			if err != nil {
+				errs = append(errs, ev3dev.DeviceError{Err: fmt.Errorf("robot: LED group %q: LED %q not found: %v", name, n, err)})
+				continue
+			}
+			r.LEDs[name] = append(r.LEDs[name], l)
+		}
+	}
+	for _, name := range steeringNames(c.Steering) {
+		desc := c.Steering[name]
+		left, right := r.Motors[desc.Left], r.Motors[desc.Right]
+		if left == nil || right == nil {
+			// The failure has already been reported.
+			continue
+		}
+		var timeout time.Duration
+		if desc.Timeout != "" {
+			timeout, _ = time.ParseDuration(desc.Timeout)
+		}
+		r.Steering[name] = &motorutil.Steering{Left: left, Right: right, Timeout: timeout}
+	}
+
+	err = errs.Err()
+	if err != nil {
+		r.Release()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Release releases the device registry claims held by the motor and sensor
+// handles of the Robot.
+func (r *Robot) Release() {
+	for _, m := range r.Motors {
+		ev3dev.Release(m)
+	}
+	for _, s := range r.Sensors {
+		ev3dev.Release(s)
+	}
+}
+
+// deviceError returns an error describing a failure to find the named device.
+func deviceError(kind, name, port string, err error) error {
+	if err, ok := err.(ev3dev.DriverMismatch); ok {
+		return fmt.Errorf("robot: %s %q: mismatched driver on port %s: want %q but have %q", kind, name, port, err.Want, err.Have)
+	}
+	return fmt.Errorf("robot: %s %q: %v", kind, name, err)
+}
+
+// motorNames returns the sorted names of the motors in m.
+func motorNames(m map[string]MotorConfig) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sensorNames returns the sorted names of the sensors in m.
+func sensorNames(m map[string]SensorConfig) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ledGroupNames returns the sorted names of the LED groups in m.
+func ledGroupNames(m map[string][]string) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// steeringNames returns the sorted names of the steering pairs in m.
+func steeringNames(m map[string]SteeringConfig) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
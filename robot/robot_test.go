@@ -0,0 +1,253 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robot
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ev3go/ev3dev"
+	"github.com/ev3go/ev3dev/ev3devtest"
+)
+
+const validDescription = `{
+	"motors": {
+		"left":  {"port": "ev3-ports:outB", "driver": "lego-ev3-l-motor", "stop_action": "brake"},
+		"right": {"port": "ev3-ports:outC", "driver": "lego-ev3-l-motor", "stop_action": "brake"},
+		"arm":   {"port": "ev3-ports:outA", "driver": "lego-ev3-m-motor", "polarity": "inversed"}
+	},
+	"sensors": {
+		"touch": {"port": "ev3-ports:in1", "driver": "lego-ev3-touch"}
+	},
+	"leds": {
+		"red": ["led0:red:brick-status", "led1:red:brick-status"]
+	},
+	"steering": {
+		"drive": {"left": "left", "right": "right", "timeout": "10s"}
+	}
+}`
+
+func TestParse(t *testing.T) {
+	got, err := Parse(strings.NewReader(validDescription))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &Config{
+		Motors: map[string]MotorConfig{
+			"left":  {Port: "ev3-ports:outB", Driver: "lego-ev3-l-motor", StopAction: "brake"},
+			"right": {Port: "ev3-ports:outC", Driver: "lego-ev3-l-motor", StopAction: "brake"},
+			"arm":   {Port: "ev3-ports:outA", Driver: "lego-ev3-m-motor", Polarity: ev3dev.Inversed},
+		},
+		Sensors: map[string]SensorConfig{
+			"touch": {Port: "ev3-ports:in1", Driver: "lego-ev3-touch"},
+		},
+		LEDs: map[string][]string{
+			"red": {"led0:red:brick-status", "led1:red:brick-status"},
+		},
+		Steering: map[string]SteeringConfig{
+			"drive": {Left: "left", Right: "right", Timeout: "10s"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected config:\ngot: %+v\nwant:%+v", got, want)
+	}
+}
+
+var invalidDescriptionTests = []struct {
+	desc     string
+	wantErrs []string
+}{
+	{
+		desc:     `{"motors": {"left": {"port": "ev3-ports:outB", "driver": "lego-ev3-l-motor", "speed": 10}}}`,
+		wantErrs: []string{`robot: failed to parse description: json: unknown field "speed"`},
+	},
+	{
+		desc: `{"motors": {"left": {"driver": "lego-ev3-l-motor", "polarity": "backwards"}}}`,
+		wantErrs: []string{
+			`robot: motor "left": missing port`,
+			`robot: motor "left": invalid polarity "backwards"`,
+		},
+	},
+	{
+		desc: `{
+			"motors": {"left": {"port": "ev3-ports:outB", "driver": "lego-ev3-l-motor"}},
+			"sensors": {"touch": {"port": "ev3-ports:outB"}}
+		}`,
+		wantErrs: []string{
+			`robot: sensor "touch": port ev3-ports:outB already used by motor "left"`,
+			`robot: sensor "touch": missing driver`,
+		},
+	},
+	{
+		desc: `{
+			"motors": {"left": {"port": "ev3-ports:outB", "driver": "lego-ev3-l-motor"}},
+			"steering": {
+				"drive": {"left": "left", "right": "right", "timeout": "soon"},
+				"spin": {"left": "left", "right": "left"}
+			}
+		}`,
+		wantErrs: []string{
+			`robot: steering "drive": right motor "right" not declared`,
+			`robot: steering "drive": invalid timeout: time: invalid duration "soon"`,
+			`robot: steering "spin": left and right motors are both "left"`,
+		},
+	},
+	{
+		desc:     `{"leds": {"status": []}}`,
+		wantErrs: []string{`robot: LED group "status": no LEDs`},
+	},
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, test := range invalidDescriptionTests {
+		_, err := Parse(strings.NewReader(test.desc))
+		if err == nil {
+			t.Errorf("expected error for %s", test.desc)
+			continue
+		}
+		var got []string
+		if errs, ok := err.(ConfigErrors); ok {
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+		} else {
+			got = []string{err.Error()}
+		}
+		if !reflect.DeepEqual(got, test.wantErrs) {
+			t.Errorf("unexpected errors for %s:\ngot: %q\nwant:%q", test.desc, got, test.wantErrs)
+		}
+	}
+}
+
+// newEV3 returns a started fake EV3 with the devices of validDescription
+// and a color sensor.
+func newEV3(t *testing.T) *ev3devtest.Brick {
+	b := ev3devtest.NewEV3()
+	b.AddTachoMotor("ev3-ports:outA", "lego-ev3-m-motor")
+	b.AddTachoMotor("ev3-ports:outB", "lego-ev3-l-motor")
+	b.AddTachoMotor("ev3-ports:outC", "lego-ev3-l-motor")
+	b.AddSensor("ev3-ports:in1", "lego-ev3-touch", "TOUCH")
+	b.AddSensor("ev3-ports:in2", "lego-ev3-color", "COL-REFLECT", "COL-COLOR")
+	err := b.Start("")
+	if err != nil {
+		t.Fatalf("unexpected error starting brick: %v", err)
+	}
+	return b
+}
+
+func TestLoad(t *testing.T) {
+	b := newEV3(t)
+	defer b.Close()
+
+	f, err := ioutil.TempFile("", "robot")
+	if err != nil {
+		t.Fatalf("failed to make temporary file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	desc := strings.Replace(validDescription,
+		`"touch": {"port": "ev3-ports:in1", "driver": "lego-ev3-touch"}`,
+		`"touch": {"port": "ev3-ports:in1", "driver": "lego-ev3-touch"},
+		"color": {"port": "ev3-ports:in2", "driver": "lego-ev3-color", "mode": "COL-COLOR"}`, 1)
+	_, err = f.WriteString(desc)
+	f.Close()
+	if err != nil {
+		t.Fatalf("failed to write description: %v", err)
+	}
+
+	r, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Release()
+
+	for _, c := range []struct {
+		class, name, attr string
+		want              string
+	}{
+		{ev3dev.TachoMotorClass, "motor0", "polarity", "inversed"},
+		{ev3dev.TachoMotorClass, "motor1", "stop_action", "brake"},
+		{ev3dev.TachoMotorClass, "motor2", "stop_action", "brake"},
+		{ev3dev.SensorClass, "sensor1", "mode", "COL-COLOR"},
+	} {
+		got := b.Device(c.class, c.name).Attr(c.attr)
+		if got != c.want {
+			t.Errorf("unexpected %s value for %s/%s: got:%q want:%q", c.attr, c.class, c.name, got, c.want)
+		}
+	}
+	for name, want := range map[string]string{"arm": "motor0", "left": "motor1", "right": "motor2"} {
+		if got := r.Motors[name].String(); got != want {
+			t.Errorf("unexpected device for motor %q: got:%s want:%s", name, got, want)
+		}
+	}
+	if got := r.Sensors["color"].String(); got != "sensor1" {
+		t.Errorf("unexpected device for sensor \"color\": got:%s want:sensor1", got)
+	}
+	if got := len(r.LEDs["red"]); got != 2 {
+		t.Errorf("unexpected number of LEDs in group \"red\": got:%d want:2", got)
+	}
+	s := r.Steering["drive"]
+	if s == nil {
+		t.Fatal("missing steering \"drive\"")
+	}
+	if s.Left != r.Motors["left"] || s.Right != r.Motors["right"] || s.Timeout != 10*time.Second {
+		t.Errorf("unexpected steering: got:%+v", s)
+	}
+}
+
+var openErrorTests = []struct {
+	name    string
+	motor   string
+	wantErr string
+}{
+	{
+		name:    "wrong driver",
+		motor:   `{"port": "ev3-ports:outA", "driver": "lego-ev3-l-motor"}`,
+		wantErr: `robot: motor "arm": mismatched driver on port ev3-ports:outA: want "lego-ev3-l-motor" but have "lego-ev3-m-motor"`,
+	},
+	{
+		name:    "missing port",
+		motor:   `{"port": "ev3-ports:outD", "driver": "lego-ev3-m-motor"}`,
+		wantErr: `robot: motor "arm": ev3dev: could not find device for driver "lego-ev3-m-motor" on port ev3-ports:outD`,
+	},
+	{
+		name:    "invalid stop action",
+		motor:   `{"port": "ev3-ports:outA", "driver": "lego-ev3-m-motor", "stop_action": "float"}`,
+		wantErr: `robot: motor "arm": failed to configure: ev3dev: invalid value for motor0 stop_action: "float"`,
+	},
+}
+
+func TestOpenError(t *testing.T) {
+	for _, test := range openErrorTests {
+		t.Run(test.name, func(t *testing.T) {
+			b := newEV3(t)
+			defer b.Close()
+
+			desc := strings.Replace(validDescription,
+				`{"port": "ev3-ports:outA", "driver": "lego-ev3-m-motor", "polarity": "inversed"}`, test.motor, 1)
+			c, err := Parse(strings.NewReader(desc))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			r, err := c.Open()
+			if err == nil {
+				r.Release()
+				t.Fatal("expected error")
+			}
+			if r != nil {
+				t.Errorf("unexpected robot returned with error: %+v", r)
+			}
+			if !strings.HasPrefix(err.Error(), test.wantErr) {
+				t.Errorf("unexpected error:\ngot: %q\nwant:%q", err, test.wantErr)
+			}
+			if claims := ev3dev.Claims(); len(claims) != 0 {
+				t.Errorf("unexpected claims after failed open: %v", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Access is the access mode of a sysfs attribute.
+type Access int
+
+const (
+	// Read indicates the attribute can be read.
+	Read Access = 1 << iota
+
+	// Write indicates the attribute can be written.
+	Write
+
+	// ReadWrite indicates the attribute can be
+	// read and written.
+	ReadWrite = Read | Write
+)
+
+// String satisfies the fmt.Stringer interface.
+func (a Access) String() string {
+	switch a {
+	case Read:
+		return "r"
+	case Write:
+		return "w"
+	case ReadWrite:
+		return "rw"
+	default:
+		return "-"
+	}
+}
+
+// AttrType is the type of the value held by a sysfs attribute.
+type AttrType int
+
+const (
+	// StringAttr is a single string value.
+	StringAttr AttrType = iota
+
+	// StringListAttr is a space separated list
+	// of string values.
+	StringListAttr
+
+	// IntAttr is a decimal integer value.
+	IntAttr
+
+	// DurationAttr is a decimal integer duration
+	// value in the units of the attribute.
+	DurationAttr
+
+	// StateAttr is a space separated list of
+	// motor state flags.
+	StateAttr
+
+	// UeventAttr is a newline separated list
+	// of KEY=VALUE pairs.
+	UeventAttr
+
+	// BinaryAttr is raw binary data.
+	BinaryAttr
+)
+
+// String satisfies the fmt.Stringer interface.
+func (t AttrType) String() string {
+	switch t {
+	case StringAttr:
+		return "string"
+	case StringListAttr:
+		return "string list"
+	case IntAttr:
+		return "int"
+	case DurationAttr:
+		return "duration"
+	case StateAttr:
+		return "state"
+	case UeventAttr:
+		return "uevent"
+	case BinaryAttr:
+		return "binary"
+	default:
+		return "unknown"
+	}
+}
+
+// Attr is a description of a sysfs device attribute.
+type Attr struct {
+	// Name is the name of the attribute
+	// file relative to the device directory.
+	Name string
+
+	Access Access
+	Type   AttrType
+
+	// Units is the unit of the attribute's
+	// value, or empty if the value is unitless.
+	Units string
+
+	// HasRange indicates that Min and Max
+	// hold the inclusive range of valid
+	// values for an integer or duration
+	// attribute.
+	HasRange bool
+	Min, Max int
+
+	// Values is the set of valid values for
+	// attributes with a fixed set of values.
+	// Values is nil if the set of valid values
+	// is not fixed or depends on the device.
+	Values []string
+}
+
+// Validate returns an error if value is not a valid value to write to the
+// attribute.
+func (a Attr) Validate(value string) error {
+	if a.Access&Write == 0 {
+		return fmt.Errorf("ev3dev: attribute %s is not writable", a.Name)
+	}
+	switch a.Type {
+	case IntAttr, DurationAttr:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf(wrapped("ev3dev: invalid value for %s: %w"), a.Name, err)
+		}
+		if a.HasRange && (v < a.Min || a.Max < v) {
+			return fmt.Errorf("ev3dev: invalid value for %s: %d (must be in %d-%d)", a.Name, v, a.Min, a.Max)
+		}
+	}
+	if a.Values != nil {
+		for _, v := range a.Values {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("ev3dev: invalid value for %s: %q (valid:%q)", a.Name, value, a.Values)
+	}
+	return nil
+}
+
+// clone returns a copy of a that does not share its Values.
+func (a Attr) clone() Attr {
+	if a.Values != nil {
+		a.Values = append([]string(nil), a.Values...)
+	}
+	return a
+}
+
+// These are the units used by attribute descriptions.
+const (
+	countUnits     = "tacho counts"
+	countRateUnits = "tacho counts/s"
+	percentUnits   = "%"
+	msUnits        = "ms"
+	usUnits        = "µs"
+	microvoltUnits = "µV"
+	microampUnits  = "µA"
+)
+
+// These are the limits used by attribute descriptions.
+const (
+	int32Min        = math.MinInt32
+	int32Max        = math.MaxInt32
+	dcRampMaxMillis = 10000
+)
+
+// maxMillis is the longest duration that can be written
+// to a millisecond attribute.
+const maxMillis = int32Max * time.Millisecond
+
+var (
+	polarities = []string{string(Normal), string(Inversed)}
+
+	addressAttr    = Attr{Name: address, Access: Read, Type: StringAttr}
+	driverNameAttr = Attr{Name: driverName, Access: Read, Type: StringAttr}
+	commandAttr    = Attr{Name: command, Access: Write, Type: StringAttr}
+	commandsAttr   = Attr{Name: commands, Access: Read, Type: StringListAttr}
+	ueventAttr     = Attr{Name: uevent, Access: Read, Type: UeventAttr}
+	stateAttr      = Attr{Name: state, Access: Read, Type: StateAttr}
+	polarityAttr   = Attr{Name: polarity, Access: ReadWrite, Type: StringAttr, Values: polarities}
+
+	dutyCycleAttr         = Attr{Name: dutyCycle, Access: Read, Type: IntAttr, Units: percentUnits}
+	dutyCycleSetpointAttr = Attr{Name: dutyCycleSetpoint, Access: ReadWrite, Type: IntAttr, Units: percentUnits, HasRange: true, Min: -100, Max: 100}
+	stopActionAttr        = Attr{Name: stopAction, Access: ReadWrite, Type: StringAttr}
+	stopActionsAttr       = Attr{Name: stopActions, Access: Read, Type: StringListAttr}
+)
+
+// tachoAttrs returns the attributes common to tacho-motors and linear actuators.
+func tachoAttrs(extra ...Attr) []Attr {
+	attrs := []Attr{
+		addressAttr,
+		driverNameAttr,
+		commandAttr,
+		commandsAttr,
+	}
+	attrs = append(attrs, extra...)
+	return append(attrs,
+		dutyCycleAttr,
+		dutyCycleSetpointAttr,
+		Attr{Name: holdPIDkd, Access: ReadWrite, Type: IntAttr},
+		Attr{Name: holdPIDki, Access: ReadWrite, Type: IntAttr},
+		Attr{Name: holdPIDkp, Access: ReadWrite, Type: IntAttr},
+		Attr{Name: maxSpeed, Access: Read, Type: IntAttr, Units: countRateUnits},
+		polarityAttr,
+		Attr{Name: position, Access: ReadWrite, Type: IntAttr, Units: countUnits, HasRange: true, Min: int32Min, Max: int32Max},
+		Attr{Name: positionSetpoint, Access: ReadWrite, Type: IntAttr, Units: countUnits, HasRange: true, Min: int32Min, Max: int32Max},
+		Attr{Name: rampDownSetpoint, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		Attr{Name: rampUpSetpoint, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		Attr{Name: speed, Access: Read, Type: IntAttr, Units: countRateUnits},
+		Attr{Name: speedSetpoint, Access: ReadWrite, Type: IntAttr, Units: countRateUnits},
+		Attr{Name: speedPIDkd, Access: ReadWrite, Type: IntAttr},
+		Attr{Name: speedPIDki, Access: ReadWrite, Type: IntAttr},
+		Attr{Name: speedPIDkp, Access: ReadWrite, Type: IntAttr},
+		stateAttr,
+		stopActionAttr,
+		stopActionsAttr,
+		Attr{Name: timeSetpoint, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		ueventAttr,
+	)
+}
+
+// sensorValueAttrs returns the value0 to value7 sensor attributes.
+func sensorValueAttrs() []Attr {
+	attrs := make([]Attr, maxSensorValues)
+	for i := range attrs {
+		attrs[i] = Attr{Name: value + strconv.Itoa(i), Access: Read, Type: IntAttr}
+	}
+	return attrs
+}
+
+// schema holds the attribute descriptions for each device class.
+var schema = map[string][]Attr{
+	LegoPortClass: {
+		addressAttr,
+		driverNameAttr,
+		Attr{Name: modes, Access: Read, Type: StringListAttr},
+		Attr{Name: mode, Access: ReadWrite, Type: StringAttr},
+		Attr{Name: setDevice, Access: Write, Type: StringAttr},
+		Attr{Name: status, Access: Read, Type: StringAttr},
+		ueventAttr,
+	},
+	SensorClass: append([]Attr{
+		addressAttr,
+		driverNameAttr,
+		{Name: binData, Access: Read, Type: BinaryAttr},
+		{Name: binDataFormat, Access: Read, Type: StringAttr},
+		commandAttr,
+		commandsAttr,
+		{Name: decimals, Access: Read, Type: IntAttr},
+		{Name: direct, Access: ReadWrite, Type: BinaryAttr},
+		{Name: firmwareVersion, Access: Read, Type: StringAttr},
+		{Name: mode, Access: ReadWrite, Type: StringAttr},
+		{Name: modes, Access: Read, Type: StringListAttr},
+		{Name: numValues, Access: Read, Type: IntAttr},
+		{Name: pollRate, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		{Name: textValues, Access: Read, Type: StringListAttr},
+		ueventAttr,
+		{Name: units, Access: Read, Type: StringAttr},
+	}, sensorValueAttrs()...),
+	TachoMotorClass: tachoAttrs(
+		Attr{Name: countPerRot, Access: Read, Type: IntAttr, Units: countUnits},
+	),
+	LinearActuatorClass: tachoAttrs(
+		Attr{Name: countPerMeter, Access: Read, Type: IntAttr, Units: countUnits},
+		Attr{Name: fullTravelCount, Access: Read, Type: IntAttr, Units: countUnits},
+	),
+	ServoMotorClass: {
+		addressAttr,
+		driverNameAttr,
+		commandAttr,
+		Attr{Name: maxPulseSetpoint, Access: ReadWrite, Type: DurationAttr, Units: usUnits, HasRange: true, Min: 2300, Max: 2700},
+		Attr{Name: midPulseSetpoint, Access: ReadWrite, Type: DurationAttr, Units: usUnits, HasRange: true, Min: 1300, Max: 1700},
+		Attr{Name: minPulseSetpoint, Access: ReadWrite, Type: DurationAttr, Units: usUnits, HasRange: true, Min: 300, Max: 700},
+		polarityAttr,
+		Attr{Name: positionSetpoint, Access: ReadWrite, Type: IntAttr, Units: percentUnits, HasRange: true, Min: -100, Max: 100},
+		Attr{Name: rateSetpoint, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		stateAttr,
+		ueventAttr,
+	},
+	DCMotorClass: {
+		addressAttr,
+		driverNameAttr,
+		commandAttr,
+		commandsAttr,
+		dutyCycleAttr,
+		dutyCycleSetpointAttr,
+		polarityAttr,
+		Attr{Name: rampDownSetpoint, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: dcRampMaxMillis},
+		Attr{Name: rampUpSetpoint, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: dcRampMaxMillis},
+		stateAttr,
+		stopActionAttr,
+		stopActionsAttr,
+		Attr{Name: timeSetpoint, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		ueventAttr,
+	},
+	LEDClass: {
+		Attr{Name: brightness, Access: ReadWrite, Type: IntAttr},
+		Attr{Name: delayOff, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		Attr{Name: delayOn, Access: ReadWrite, Type: DurationAttr, Units: msUnits, HasRange: true, Min: 0, Max: int32Max},
+		Attr{Name: maxBrightness, Access: Read, Type: IntAttr},
+		Attr{Name: trigger, Access: ReadWrite, Type: StringListAttr},
+		ueventAttr,
+	},
+	PowerSupplyClass: {
+		Attr{Name: currentNow, Access: Read, Type: IntAttr, Units: microampUnits},
+		Attr{Name: batteryTechnology, Access: Read, Type: StringAttr},
+		Attr{Name: batteryType, Access: Read, Type: StringAttr},
+		ueventAttr,
+		Attr{Name: voltageMaxDesign, Access: Read, Type: IntAttr, Units: microvoltUnits},
+		Attr{Name: voltageMinDesign, Access: Read, Type: IntAttr, Units: microvoltUnits},
+		Attr{Name: voltageNow, Access: Read, Type: IntAttr, Units: microvoltUnits},
+	},
+}
+
+// Schema returns the descriptions of the sysfs attributes of devices in the
+// given device class. The class must be one of the device class names used
+// by DeviceInfo. Schema returns nil for an unknown class.
+func Schema(class string) []Attr {
+	attrs, ok := schema[class]
+	if !ok {
+		return nil
+	}
+	s := make([]Attr, len(attrs))
+	for i, a := range attrs {
+		s[i] = a.clone()
+	}
+	return s
+}
+
+// AttrFor returns the description of the named sysfs attribute in the given
+// device class and whether the attribute exists in the class.
+func AttrFor(class, name string) (Attr, bool) {
+	for _, a := range schema[class] {
+		if a.Name == name {
+			return a.clone(), true
+		}
+	}
+	return Attr{}, false
+}
+
+// ClassOf returns the device class name of d as used by DeviceInfo.
+func ClassOf(d Device) string {
+	switch d.(type) {
+	case *LegoPort:
+		return LegoPortClass
+	case *Sensor:
+		return SensorClass
+	case *TachoMotor:
+		return TachoMotorClass
+	case *LinearActuator:
+		return LinearActuatorClass
+	case *ServoMotor:
+		return ServoMotorClass
+	case *DCMotor:
+		return DCMotorClass
+	case ledDevice:
+		return LEDClass
+	case powerDevice:
+		return PowerSupplyClass
+	default:
+		return ""
+	}
+}
+
+// ReadAttr returns the value of the named attribute of d. The attribute must
+// be a readable attribute in the schema for the class of d.
+func ReadAttr(d Device, name string) (string, error) {
+	a, ok := AttrFor(ClassOf(d), name)
+	if !ok {
+		return "", fmt.Errorf("ev3dev: no attribute %s for %s", name, d)
+	}
+	if a.Access&Read == 0 {
+		return "", fmt.Errorf("ev3dev: attribute %s of %s is not readable", name, d)
+	}
+	return stringFrom(attributeOf(d, name))
+}
+
+// WriteAttr writes value to the named attribute of d after validating it
+// against the schema for the class of d. Values cached by the device handle
+// are not updated by WriteAttr, so attributes that change cached values,
+// such as a sensor's mode, should be set using the handle's methods.
+func WriteAttr(d Device, name, value string) error {
+	a, ok := AttrFor(ClassOf(d), name)
+	if !ok {
+		return fmt.Errorf("ev3dev: no attribute %s for %s", name, d)
+	}
+	err := a.Validate(value)
+	if err != nil {
+		return err
+	}
+	return setAttributeOf(d, name, value)
+}
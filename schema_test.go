@@ -0,0 +1,260 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSchema(t *testing.T) {
+	for _, c := range inventoryClasses {
+		attrs := Schema(c.class)
+		if len(attrs) == 0 {
+			t.Errorf("no schema for %s", c.class)
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, a := range attrs {
+			if seen[a.Name] {
+				t.Errorf("duplicate attribute %s in %s schema", a.Name, c.class)
+			}
+			seen[a.Name] = true
+			if a.Access&ReadWrite == 0 {
+				t.Errorf("attribute %s in %s schema has no access", a.Name, c.class)
+			}
+			if a.HasRange && a.Min > a.Max {
+				t.Errorf("attribute %s in %s schema has invalid range: %d-%d", a.Name, c.class, a.Min, a.Max)
+			}
+		}
+	}
+	if Schema("unknown") != nil {
+		t.Error("unexpected schema for unknown class")
+	}
+
+	a, ok := AttrFor(TachoMotorClass, polarity)
+	if !ok {
+		t.Fatal("no polarity attribute for tacho-motor")
+	}
+	a.Values[0] = "mutated"
+	a, _ = AttrFor(TachoMotorClass, polarity)
+	if a.Values[0] != string(Normal) {
+		t.Error("schema values mutated through returned attribute")
+	}
+}
+
+func TestAttrValidate(t *testing.T) {
+	for _, test := range []struct {
+		class, attr, value string
+		ok                 bool
+	}{
+		{class: TachoMotorClass, attr: dutyCycleSetpoint, value: "-100", ok: true},
+		{class: TachoMotorClass, attr: dutyCycleSetpoint, value: "101", ok: false},
+		{class: TachoMotorClass, attr: dutyCycleSetpoint, value: "fast", ok: false},
+		{class: TachoMotorClass, attr: speed, value: "10", ok: false},
+		{class: TachoMotorClass, attr: polarity, value: "inversed", ok: true},
+		{class: TachoMotorClass, attr: polarity, value: "backwards", ok: false},
+		{class: TachoMotorClass, attr: timeSetpoint, value: "-1", ok: false},
+		{class: DCMotorClass, attr: rampUpSetpoint, value: "10001", ok: false},
+		{class: ServoMotorClass, attr: maxPulseSetpoint, value: "2400", ok: true},
+		{class: SensorClass, attr: command, value: "anything", ok: true},
+	} {
+		a, ok := AttrFor(test.class, test.attr)
+		if !ok {
+			t.Errorf("no %s attribute for %s", test.attr, test.class)
+			continue
+		}
+		err := a.Validate(test.value)
+		if (err == nil) != test.ok {
+			t.Errorf("unexpected validation result for %s %s %q: got error:%v want ok:%t",
+				test.class, test.attr, test.value, err, test.ok)
+		}
+	}
+}
+
+func TestReadWriteAttr(t *testing.T) {
+	const (
+		addr   = "ev3-ports:outA"
+		driver = "lego-ev3-l-motor"
+	)
+	dir := filepath.Join(TachoMotorPath, "motor0")
+	_, done := fakeRoot(t, map[string]string{
+		filepath.Join(dir, address):           addr + "\n",
+		filepath.Join(dir, driverName):        driver + "\n",
+		filepath.Join(dir, countPerRot):       "360\n",
+		filepath.Join(dir, maxSpeed):          "1050\n",
+		filepath.Join(dir, commands):          "run-forever stop reset\n",
+		filepath.Join(dir, stopActions):       "coast brake hold\n",
+		filepath.Join(dir, dutyCycleSetpoint): "0\n",
+	})
+	defer done()
+
+	m, err := TachoMotorFor(addr, driver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if class := ClassOf(m); class != TachoMotorClass {
+		t.Errorf("unexpected class: got:%s want:%s", class, TachoMotorClass)
+	}
+
+	err = WriteAttr(m, dutyCycleSetpoint, "-40")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := ReadAttr(m, dutyCycleSetpoint)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "-40" {
+		t.Errorf("unexpected value: got:%q want:%q", got, "-40")
+	}
+
+	err = WriteAttr(m, dutyCycleSetpoint, "200")
+	if err == nil {
+		t.Error("expected error for out of range value")
+	}
+	err = WriteAttr(m, countPerRot, "1")
+	if err == nil {
+		t.Error("expected error for read only attribute")
+	}
+	_, err = ReadAttr(m, command)
+	if err == nil {
+		t.Error("expected error for write only attribute")
+	}
+	_, err = ReadAttr(m, "no_such_attribute")
+	if err == nil {
+		t.Error("expected error for unknown attribute")
+	}
+}
+
+// schemaSetters returns the handle setters for the writable attributes of
+// each class that have a schema range or value list. Integer setters are
+// passed the value written to the attribute.
+func schemaSetters() map[string]map[string]func(v interface{}) error {
+	ms := func(v interface{}) time.Duration { return time.Duration(v.(int)) * time.Millisecond }
+	pol := func(v interface{}) Polarity { return Polarity(v.(string)) }
+
+	tacho := &TachoMotor{id: 0}
+	linear := &LinearActuator{id: 0}
+	servo := &ServoMotor{id: 0}
+	dc := &DCMotor{id: 0}
+	sensor := &Sensor{id: 0}
+	led := &LED{Name: LEDName("led0:red:brick-status")}
+
+	return map[string]map[string]func(v interface{}) error{
+		TachoMotorClass: {
+			dutyCycleSetpoint: func(v interface{}) error { return tacho.SetDutyCycleSetpoint(v.(int)).Err() },
+			polarity:          func(v interface{}) error { return tacho.SetPolarity(pol(v)).Err() },
+			position:          func(v interface{}) error { return tacho.SetPosition(v.(int)).Err() },
+			positionSetpoint:  func(v interface{}) error { return tacho.SetPositionSetpoint(v.(int)).Err() },
+			rampDownSetpoint:  func(v interface{}) error { return tacho.SetRampDownSetpoint(ms(v)).Err() },
+			rampUpSetpoint:    func(v interface{}) error { return tacho.SetRampUpSetpoint(ms(v)).Err() },
+			timeSetpoint:      func(v interface{}) error { return tacho.SetTimeSetpoint(ms(v)).Err() },
+		},
+		LinearActuatorClass: {
+			dutyCycleSetpoint: func(v interface{}) error { return linear.SetDutyCycleSetpoint(v.(int)).Err() },
+			polarity:          func(v interface{}) error { return linear.SetPolarity(pol(v)).Err() },
+			position:          func(v interface{}) error { return linear.SetPosition(v.(int)).Err() },
+			positionSetpoint:  func(v interface{}) error { return linear.SetPositionSetpoint(v.(int)).Err() },
+			rampDownSetpoint:  func(v interface{}) error { return linear.SetRampDownSetpoint(ms(v)).Err() },
+			rampUpSetpoint:    func(v interface{}) error { return linear.SetRampUpSetpoint(ms(v)).Err() },
+			timeSetpoint:      func(v interface{}) error { return linear.SetTimeSetpoint(ms(v)).Err() },
+		},
+		ServoMotorClass: {
+			// The pulse setpoint setters write their
+			// argument in units of time.Millisecond.
+			maxPulseSetpoint: func(v interface{}) error { return servo.SetMaxPulseSetpoint(ms(v)).Err() },
+			midPulseSetpoint: func(v interface{}) error { return servo.SetMidPulseSetpoint(ms(v)).Err() },
+			minPulseSetpoint: func(v interface{}) error { return servo.SetMinPulseSetpoint(ms(v)).Err() },
+			polarity:         func(v interface{}) error { return servo.SetPolarity(pol(v)).Err() },
+			positionSetpoint: func(v interface{}) error { return servo.SetPositionSetpoint(v.(int)).Err() },
+			rateSetpoint:     func(v interface{}) error { return servo.SetRateSetpoint(ms(v)).Err() },
+		},
+		DCMotorClass: {
+			dutyCycleSetpoint: func(v interface{}) error { return dc.SetDutyCycleSetpoint(v.(int)).Err() },
+			polarity:          func(v interface{}) error { return dc.SetPolarity(pol(v)).Err() },
+			rampDownSetpoint:  func(v interface{}) error { return dc.SetRampDownSetpoint(ms(v)).Err() },
+			rampUpSetpoint:    func(v interface{}) error { return dc.SetRampUpSetpoint(ms(v)).Err() },
+			timeSetpoint:      func(v interface{}) error { return dc.SetTimeSetpoint(ms(v)).Err() },
+		},
+		SensorClass: {
+			pollRate: func(v interface{}) error { return sensor.SetPollRate(ms(v)).Err() },
+		},
+		LEDClass: {
+			delayOff: func(v interface{}) error { return led.SetDelayOff(ms(v)).Err() },
+			delayOn:  func(v interface{}) error { return led.SetDelayOn(ms(v)).Err() },
+		},
+	}
+}
+
+func TestSchemaMatchesSetters(t *testing.T) {
+	dirs := map[string]string{
+		TachoMotorClass:     filepath.Join(TachoMotorPath, "motor0"),
+		LinearActuatorClass: filepath.Join(TachoMotorPath, "linear0"),
+		ServoMotorClass:     filepath.Join(ServoMotorPath, "motor0"),
+		DCMotorClass:        filepath.Join(DCMotorPath, "motor0"),
+		SensorClass:         filepath.Join(SensorPath, "sensor0"),
+		LEDClass:            filepath.Join(LEDPath, "led0:red:brick-status"),
+	}
+	files := make(map[string]string)
+	for class, dir := range dirs {
+		for _, a := range Schema(class) {
+			files[filepath.Join(dir, a.Name)] = ""
+		}
+	}
+	_, done := fakeRoot(t, files)
+	defer done()
+
+	setters := schemaSetters()
+	for _, c := range inventoryClasses {
+		for _, a := range Schema(c.class) {
+			if a.Access&Write == 0 || (!a.HasRange && a.Values == nil) {
+				continue
+			}
+			set, ok := setters[c.class][a.Name]
+			if !ok {
+				t.Errorf("no setter for %s %s", c.class, a.Name)
+				continue
+			}
+
+			for _, v := range a.Values {
+				err := set(v)
+				if err != nil {
+					t.Errorf("unexpected error for %s %s value %q in schema: %v", c.class, a.Name, v, err)
+				}
+			}
+			if a.Values != nil {
+				err := set("invalid")
+				if _, ok := err.(ValidValuer); !ok {
+					t.Errorf("expected invalid value error for %s %s value not in schema: got:%v", c.class, a.Name, err)
+				}
+			}
+
+			if !a.HasRange {
+				continue
+			}
+			for _, v := range []int{a.Min, a.Max} {
+				err := set(v)
+				if err != nil {
+					t.Errorf("unexpected error for %s %s value %d in schema range: %v", c.class, a.Name, v, err)
+				}
+			}
+			for _, v := range []int64{int64(a.Min) - 1, int64(a.Max) + 1} {
+				if int64(int(v)) != v {
+					// The value cannot be passed
+					// to the setter on this platform.
+					continue
+				}
+				err := set(int(v))
+				switch err.(type) {
+				case ValidRanger, ValidDurationRanger:
+				default:
+					t.Errorf("expected out of range error for %s %s value %d outside schema range: got:%v", c.class, a.Name, v, err)
+				}
+			}
+		}
+	}
+}
@@ -5,7 +5,6 @@
 package ev3dev
 
 import (
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -26,6 +25,10 @@ type Sensor struct {
 	decimals, numValues        int
 	mode, units, binDataFormat string
 
+	// files holds open attribute files
+	// when KeepFilesOpen is enabled.
+	files *fileCache
+
 	err error
 }
 
@@ -52,7 +55,11 @@ func (s *Sensor) Err() error {
 
 // idInt and setID satisfy the idSetter interface.
 func (s *Sensor) setID(id int) error {
-	t := Sensor{id: id}
+	// Open files belong to the previous device.
+	files := s.files
+	files.invalidate()
+
+	t := Sensor{id: id, files: files}
 	var err error
 	t.firmwareVersion, err = stringFrom(attributeOf(&t, firmwareVersion))
 	if err != nil {
@@ -78,7 +85,7 @@ func (s *Sensor) setID(id int) error {
 	return nil
 
 fail:
-	*s = Sensor{id: -1}
+	*s = Sensor{id: -1, files: files}
 	return err
 }
 func (s *Sensor) idInt() int {
@@ -126,7 +133,7 @@ func (s *Sensor) BinData() ([]byte, error) {
 		return nil, err
 	}
 	path := filepath.Join(s.Path(), s.String(), binData)
-	b, err := ioutil.ReadFile(path)
+	b, err := readFile(path)
 	if err != nil {
 		return nil, newAttrOpError(s, binData, string(b), "read", err)
 	}
@@ -170,6 +177,9 @@ func (s *Sensor) Commands() []string {
 // Command issues a command to the Sensor.
 func (s *Sensor) Command(comm string) *Sensor {
 	if s.err != nil {
+		if diagnose {
+			s.err = skipped(s.err, comm)
+		}
 		return s
 	}
 	ok := false
@@ -181,9 +191,12 @@ func (s *Sensor) Command(comm string) *Sensor {
 	}
 	if !ok {
 		s.err = newInvalidValueError(s, command, "", comm, s.Commands())
+		if logger != nil {
+			logOp(s, "command", LogInfo, command, comm, s.err)
+		}
 		return s
 	}
-	s.err = setAttributeOf(s, command, comm)
+	s.err = setLoggedAttributeOf(s, "command", LogInfo, command, comm)
 	return s
 }
 
@@ -231,6 +244,9 @@ func (s *Sensor) Mode() (string, error) {
 // cached values for BinDataFormat, Decimals, Mode, NumValues and Units.
 func (s *Sensor) SetMode(m string) *Sensor {
 	if s.err != nil {
+		if diagnose {
+			s.err = skipped(s.err, m)
+		}
 		return s
 	}
 	ok := false
@@ -242,9 +258,15 @@ func (s *Sensor) SetMode(m string) *Sensor {
 	}
 	if !ok {
 		s.err = newInvalidValueError(s, mode, "", m, s.Modes())
+		if logger != nil {
+			logOp(s, "mode", LogInfo, mode, m, s.err)
+		}
 		return s
 	}
-	s.err = setAttributeOf(s, mode, m)
+	s.err = setLoggedAttributeOf(s, "mode", LogInfo, mode, m)
+	if s.err == nil {
+		s.err = s.files.invalidate()
+	}
 	if s.err == nil {
 		s.err = s.cacheModeAttrs()
 	}
@@ -289,6 +311,17 @@ func (s *Sensor) PollRate() (time.Duration, error) {
 // SetPollRate sets the polling rate value for the Sensor.
 func (s *Sensor) SetPollRate(d time.Duration) *Sensor {
 	if s.err != nil {
+		if diagnose {
+			s.err = skipped(s.err, d)
+		}
+		return s
+	}
+	if d < 0 {
+		s.err = newNegativeDurationError(s, pollRate, d)
+		return s
+	}
+	if maxMillis < d {
+		s.err = newDurationOutOfRangeError(s, pollRate, d, 0, maxMillis)
 		return s
 	}
 	s.err = setAttributeOf(s, pollRate, strconv.Itoa(int(d/time.Millisecond)))
@@ -19,6 +19,10 @@ type ServoMotor struct {
 	// Cached value:
 	driver string
 
+	// tx holds the writes staged
+	// by a Transaction.
+	tx *transaction
+
 	err error
 }
 
@@ -109,6 +113,9 @@ func (m *ServoMotor) Commands() []string {
 // Command issues a command to the ServoMotor.
 func (m *ServoMotor) Command(comm string) *ServoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, comm)
+		}
 		return m
 	}
 	avail := m.Commands()
@@ -121,9 +128,12 @@ func (m *ServoMotor) Command(comm string) *ServoMotor {
 	}
 	if !ok {
 		m.err = newInvalidValueError(m, command, "", comm, avail)
+		if logger != nil {
+			logOp(m, "command", LogInfo, command, comm, m.err)
+		}
 		return m
 	}
-	m.err = setAttributeOf(m, command, comm)
+	m.err = setLoggedAttributeOf(m, "command", LogInfo, command, comm)
 	return m
 }
 
@@ -135,6 +145,9 @@ func (m *ServoMotor) MaxPulseSetpoint() (time.Duration, error) {
 // SetMaxPulseSetpoint sets the max pulse setpoint value for the ServoMotor
 func (m *ServoMotor) SetMaxPulseSetpoint(sp time.Duration) *ServoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 2300*time.Millisecond || 2700*time.Millisecond < sp {
@@ -153,6 +166,9 @@ func (m *ServoMotor) MidPulseSetpoint() (time.Duration, error) {
 // SetMidPulseSetpoint sets the mid pulse setpoint value for the ServoMotor
 func (m *ServoMotor) SetMidPulseSetpoint(sp time.Duration) *ServoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 1300*time.Millisecond || 1700*time.Millisecond < sp {
@@ -171,6 +187,9 @@ func (m *ServoMotor) MinPulseSetpoint() (time.Duration, error) {
 // SetMinPulseSetpoint sets the min pulse setpoint value for the ServoMotor
 func (m *ServoMotor) SetMinPulseSetpoint(sp time.Duration) *ServoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 300*time.Millisecond || 700*time.Millisecond < sp {
@@ -190,6 +209,9 @@ func (m *ServoMotor) Polarity() (Polarity, error) {
 // SetPolarity sets the polarity of the ServoMotor
 func (m *ServoMotor) SetPolarity(p Polarity) *ServoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, p)
+		}
 		return m
 	}
 	if p != Normal && p != Inversed {
@@ -209,6 +231,9 @@ func (m *ServoMotor) PositionSetpoint() (int, error) {
 // SetPositionSetpoint sets the position value for the ServoMotor.
 func (m *ServoMotor) SetPositionSetpoint(sp int) *ServoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < -100 || 100 < sp {
@@ -227,12 +252,19 @@ func (m *ServoMotor) RateSetpoint() (time.Duration, error) {
 // SetRateSetpoint sets the rate setpoint value for the ServoMotor.
 func (m *ServoMotor) SetRateSetpoint(sp time.Duration) *ServoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, rateSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, rateSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, rateSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
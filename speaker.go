@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 )
 
 const (
@@ -25,6 +26,10 @@ type Speaker struct {
 	path string
 	f    *os.File
 	buf  [16]byte
+
+	// replay is true when the Speaker was
+	// initialised during a replay session.
+	replay bool
 }
 
 // NewSpeaker returns a new Speaker based on the given evdev snd device path.
@@ -52,6 +57,10 @@ func hasSound(path string) (bool, error) {
 
 // Init prepares a Speaker for use.
 func (s *Speaker) Init() error {
+	if sess != nil && sess.replaying() {
+		s.replay = true
+		return nil
+	}
 	ok, err := hasSound(s.path)
 	if err != nil {
 		return err
@@ -70,6 +79,19 @@ func (s *Speaker) Init() error {
 // Tone plays a tone at the specified frequency from the ev3 speaker.
 // If freq is zero, playing is stopped.
 func (s *Speaker) Tone(freq uint32) error {
+	if sess != nil {
+		_, err := sess.do(toneOp, s.path, strconv.FormatUint(uint64(freq), 10), func() (string, error) {
+			if s.replay {
+				return "", nil
+			}
+			return "", s.tone(freq)
+		})
+		return err
+	}
+	return s.tone(freq)
+}
+
+func (s *Speaker) tone(freq uint32) error {
 	binary.LittleEndian.PutUint32(s.buf[12:16], freq)
 	_, err := s.f.Write(s.buf[:])
 	return err
@@ -78,6 +100,10 @@ func (s *Speaker) Tone(freq uint32) error {
 // Close closes the Speaker. After return, the Speaker may not be used unless
 // Init is called again.
 func (s *Speaker) Close() error {
+	if s.replay {
+		s.replay = false
+		return nil
+	}
 	err := s.f.Close()
 	s.f = nil
 	return err
@@ -22,6 +22,18 @@ type TachoMotor struct {
 	countPerRot, maxSpeed int
 	commands, stopActions []string
 
+	// res holds the resilience state for
+	// handles obtained with ResilientTachoMotorFor.
+	res *resilience
+
+	// files holds open attribute files
+	// when KeepFilesOpen is enabled.
+	files *fileCache
+
+	// tx holds the writes staged
+	// by a Transaction.
+	tx *transaction
+
 	err error
 }
 
@@ -48,7 +60,11 @@ func (m *TachoMotor) Err() error {
 
 // idInt and setID satisfy the idSetter interface.
 func (m *TachoMotor) setID(id int) error {
-	t := TachoMotor{id: id}
+	// Open files belong to the previous device.
+	files := m.files
+	files.invalidate()
+
+	t := TachoMotor{id: id, files: files}
 	var err error
 	t.countPerRot, err = intFrom(attributeOf(&t, countPerRot))
 	if err != nil {
@@ -74,7 +90,7 @@ func (m *TachoMotor) setID(id int) error {
 	return nil
 
 fail:
-	*m = TachoMotor{id: -1}
+	*m = TachoMotor{id: -1, files: files}
 	return err
 }
 func (m *TachoMotor) idInt() int {
@@ -135,6 +151,9 @@ func (m *TachoMotor) Commands() []string {
 // Command issues a command to the TachoMotor.
 func (m *TachoMotor) Command(comm string) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, comm)
+		}
 		return m
 	}
 	ok := false
@@ -146,9 +165,12 @@ func (m *TachoMotor) Command(comm string) *TachoMotor {
 	}
 	if !ok {
 		m.err = newInvalidValueError(m, command, "", comm, m.Commands())
+		if logger != nil {
+			logOp(m, "command", LogInfo, command, comm, m.err)
+		}
 		return m
 	}
-	m.err = setAttributeOf(m, command, comm)
+	m.err = setLoggedAttributeOf(m, "command", LogInfo, command, comm)
 	return m
 }
 
@@ -170,6 +192,9 @@ func (m *TachoMotor) DutyCycleSetpoint() (int, error) {
 // SetDutyCycleSetpoint sets the duty cycle setpoint value for the TachoMotor
 func (m *TachoMotor) SetDutyCycleSetpoint(sp int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < -100 || 100 < sp {
@@ -189,6 +214,9 @@ func (m *TachoMotor) Polarity() (Polarity, error) {
 // SetPolarity sets the polarity of the TachoMotor
 func (m *TachoMotor) SetPolarity(p Polarity) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, p)
+		}
 		return m
 	}
 	if p != Normal && p != Inversed {
@@ -207,6 +235,9 @@ func (m *TachoMotor) Position() (int, error) {
 // SetPosition sets the position value for the TachoMotor.
 func (m *TachoMotor) SetPosition(pos int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, pos)
+		}
 		return m
 	}
 	if pos != int(int32(pos)) {
@@ -225,6 +256,9 @@ func (m *TachoMotor) HoldPIDKd() (int, error) {
 // SetHoldPIDKd sets the derivative constant for the position PID for the TachoMotor.
 func (m *TachoMotor) SetHoldPIDKd(k int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, holdPIDkd, strconv.Itoa(k))
@@ -239,6 +273,9 @@ func (m *TachoMotor) HoldPIDKi() (int, error) {
 // SetHoldPIDKi sets the integral constant for the position PID for the TachoMotor.
 func (m *TachoMotor) SetHoldPIDKi(k int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, holdPIDki, strconv.Itoa(k))
@@ -253,6 +290,9 @@ func (m *TachoMotor) HoldPIDKp() (int, error) {
 // SetHoldPIDKp sets the proportional constant for the position PID for the TachoMotor.
 func (m *TachoMotor) SetHoldPIDKp(k int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, holdPIDkp, strconv.Itoa(k))
@@ -272,6 +312,9 @@ func (m *TachoMotor) PositionSetpoint() (int, error) {
 // SetPositionSetpoint sets the position setpoint value for the TachoMotor.
 func (m *TachoMotor) SetPositionSetpoint(sp int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp != int(int32(sp)) {
@@ -295,6 +338,9 @@ func (m *TachoMotor) SpeedSetpoint() (int, error) {
 // SetSpeedSetpoint sets the speed setpoint value for the TachoMotor.
 func (m *TachoMotor) SetSpeedSetpoint(sp int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedSetpoint, strconv.Itoa(sp))
@@ -309,12 +355,19 @@ func (m *TachoMotor) RampUpSetpoint() (time.Duration, error) {
 // SetRampUpSetpoint sets the ramp up setpoint value for the TachoMotor.
 func (m *TachoMotor) SetRampUpSetpoint(sp time.Duration) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, rampUpSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, rampUpSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, rampUpSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
@@ -327,12 +380,19 @@ func (m *TachoMotor) RampDownSetpoint() (time.Duration, error) {
 // SetRampDownSetpoint sets the ramp down setpoint value for the TachoMotor.
 func (m *TachoMotor) SetRampDownSetpoint(sp time.Duration) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, rampDownSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, rampDownSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, rampDownSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
@@ -345,6 +405,9 @@ func (m *TachoMotor) SpeedPIDKd() (int, error) {
 // SetSpeedPIDKd sets the derivative constant for the speed regulation PID for the TachoMotor.
 func (m *TachoMotor) SetSpeedPIDKd(k int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedPIDkd, strconv.Itoa(k))
@@ -359,6 +422,9 @@ func (m *TachoMotor) SpeedPIDKi() (int, error) {
 // SetSpeedPIDKi sets the integral constant for the speed regulation PID for the TachoMotor.
 func (m *TachoMotor) SetSpeedPIDKi(k int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedPIDki, strconv.Itoa(k))
@@ -373,6 +439,9 @@ func (m *TachoMotor) SpeedPIDKp() (int, error) {
 // SetSpeedPIDKp sets the proportional constant for the speed regulation PID for the TachoMotor.
 func (m *TachoMotor) SetSpeedPIDKp(k int) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, k)
+		}
 		return m
 	}
 	m.err = setAttributeOf(m, speedPIDkp, strconv.Itoa(k))
@@ -387,6 +456,45 @@ func (m *TachoMotor) State() (MotorState, error) {
 	return stateFrom(attributeOf(m, state))
 }
 
+// TachoMotorStatus is a snapshot of the dynamic state of a tacho-motor.
+type TachoMotorStatus struct {
+	// Time is the time the snapshot
+	// was taken.
+	Time time.Time
+
+	Position  int
+	Speed     int
+	DutyCycle int
+	State     MotorState
+}
+
+// Status returns the current position, speed, duty cycle and state of the
+// TachoMotor, read together with a single timestamp.
+func (m *TachoMotor) Status() (TachoMotorStatus, error) {
+	vals, t, err := ReadAttrs(m, position, speed, dutyCycle, state)
+	if err != nil {
+		return TachoMotorStatus{}, err
+	}
+	stat := TachoMotorStatus{Time: t}
+	stat.Position, err = intFrom(m, vals[0], position, nil)
+	if err != nil {
+		return TachoMotorStatus{}, err
+	}
+	stat.Speed, err = intFrom(m, vals[1], speed, nil)
+	if err != nil {
+		return TachoMotorStatus{}, err
+	}
+	stat.DutyCycle, err = intFrom(m, vals[2], dutyCycle, nil)
+	if err != nil {
+		return TachoMotorStatus{}, err
+	}
+	stat.State, err = stateFrom(m, vals[3], state, nil)
+	if err != nil {
+		return TachoMotorStatus{}, err
+	}
+	return stat, nil
+}
+
 // StopAction returns the stop action used when a stop command is issued
 // to the TachoMotor.
 func (m *TachoMotor) StopAction() (string, error) {
@@ -397,6 +505,9 @@ func (m *TachoMotor) StopAction() (string, error) {
 // issued to the TachoMotor.
 func (m *TachoMotor) SetStopAction(action string) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, action)
+		}
 		return m
 	}
 	ok := false
@@ -434,12 +545,19 @@ func (m *TachoMotor) TimeSetpoint() (time.Duration, error) {
 // SetTimeSetpoint sets the time setpoint value for the TachoMotor.
 func (m *TachoMotor) SetTimeSetpoint(sp time.Duration) *TachoMotor {
 	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err, sp)
+		}
 		return m
 	}
 	if sp < 0 {
 		m.err = newNegativeDurationError(m, timeSetpoint, sp)
 		return m
 	}
+	if maxMillis < sp {
+		m.err = newDurationOutOfRangeError(m, timeSetpoint, sp, 0, maxMillis)
+		return m
+	}
 	m.err = setAttributeOf(m, timeSetpoint, strconv.Itoa(int(sp/time.Millisecond)))
 	return m
 }
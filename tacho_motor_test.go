@@ -67,7 +67,7 @@ type tachoMotor struct {
 
 	_uevent map[string]string
 
-	t *testing.T
+	t testing.TB
 }
 
 func (m *tachoMotor) commands() []string {
@@ -1758,3 +1758,69 @@ func TestTachoMotor(t *testing.T) {
 		}
 	})
 }
+
+func BenchmarkTachoMotor(b *testing.B) {
+	const driver = "lego-ev3-l-motor"
+	conn := []tachoMotorConn{
+		{
+			id: 0,
+			tachoMotor: &tachoMotor{
+				address: "outA",
+				driver:  driver,
+
+				_commands:    []string{"run-direct", "stop"},
+				_maxSpeed:    1200,
+				_countPerRot: 360,
+				_stopActions: []string{"coast"},
+
+				_uevent: map[string]string{
+					"LEGO_ADDRESS":     "outA",
+					"LEGO_DRIVER_NAME": driver,
+				},
+
+				t: b,
+			},
+		},
+	}
+
+	fs := tachomotorsysfs(conn...)
+	unmount := serve(fs, b)
+	defer unmount()
+
+	for _, keep := range []bool{false, true} {
+		m, err := TachoMotorFor("outA", driver)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+		err = m.KeepFilesOpen(keep)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+		name := "reopen"
+		if keep {
+			name = "keep open"
+		}
+
+		b.Run("Position/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := m.Position()
+				if err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+		b.Run("SetDutyCycleSetpoint/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				err := m.SetDutyCycleSetpoint(i % 100).Err()
+				if err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+
+		err = m.KeepFilesOpen(false)
+		if err != nil {
+			b.Errorf("unexpected error: %v", err)
+		}
+	}
+}
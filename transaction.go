@@ -0,0 +1,214 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+// transaction holds the attribute writes staged by a transaction.
+type transaction struct {
+	writes []stagedWrite
+}
+
+// stagedWrite is an attribute write staged by a transaction, and
+// the message and level it is logged with when it is applied.
+type stagedWrite struct {
+	msg        string
+	level      LogLevel
+	attr, data string
+}
+
+// stager is a Device that may stage attribute writes in a transaction.
+type stager interface {
+	Device
+
+	// transaction returns the transaction staging
+	// writes to the device, or nil if no transaction
+	// is being staged.
+	transaction() *transaction
+}
+
+// stage records the write w to d if d is staging a transaction, and
+// returns whether the write was staged.
+func stage(d Device, w stagedWrite) bool {
+	s, ok := d.(stager)
+	if !ok {
+		return false
+	}
+	tx := s.transaction()
+	if tx == nil {
+		return false
+	}
+	tx.writes = append(tx.writes, w)
+	return true
+}
+
+// transact stages the attribute writes made to d by fn in a transaction
+// held in *tx, and applies them if fn leaves no error in *errp. On failure
+// the values of the applied attributes are restored in reverse order.
+func transact(d Device, tx **transaction, errp *error, fn func()) error {
+	if *tx != nil {
+		// Join the enclosing transaction.
+		fn()
+		return nil
+	}
+
+	t := &transaction{}
+	*tx = t
+	func() {
+		defer func() { *tx = nil }()
+		fn()
+	}()
+	if *errp != nil {
+		return nil
+	}
+
+	// Read the current values of all the
+	// attributes before writing any of them.
+	// Commands are actions and cannot be read
+	// or restored.
+	var (
+		attrs []string
+		prev  = make(map[string]string)
+	)
+	for _, w := range t.writes {
+		if w.attr == command {
+			continue
+		}
+		if _, ok := prev[w.attr]; ok {
+			continue
+		}
+		v, err := stringFrom(attributeOf(d, w.attr))
+		if err != nil {
+			return err
+		}
+		attrs = append(attrs, w.attr)
+		prev[w.attr] = v
+	}
+
+	applied := make(map[string]bool)
+	for _, w := range t.writes {
+		err := writeLoggedAttributeOf(d, w.msg, w.level, w.attr, w.data)
+		if err == nil {
+			applied[w.attr] = true
+			continue
+		}
+
+		errs := Errors{{Device: d, Err: err}}
+		for i := len(attrs) - 1; i >= 0; i-- {
+			attr := attrs[i]
+			if !applied[attr] {
+				continue
+			}
+			err := writeAttributeOf(d, attr, prev[attr])
+			if err != nil {
+				errs = append(errs, DeviceError{Device: d, Err: err})
+			}
+		}
+		return errs.Err()
+	}
+	return nil
+}
+
+// Transaction stages the attribute writes made by calling methods of m in fn
+// and then applies them to the TachoMotor in order. No attribute is written
+// unless all the method calls in fn succeed. Before applying the writes, the
+// current values of the written attributes are read, and if a write fails,
+// the attributes that were already written are restored to those values in
+// reverse order. Commands issued in fn are applied in order with the other
+// writes but cannot be undone.
+//
+// Attribute reads made in fn do not observe the staged writes. A Transaction
+// called within fn joins the enclosing transaction.
+func (m *TachoMotor) Transaction(fn func(m *TachoMotor)) *TachoMotor {
+	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err)
+		}
+		return m
+	}
+	err := transact(m, &m.tx, &m.err, func() { fn(m) })
+	if err != nil {
+		m.err = err
+	}
+	return m
+}
+
+func (m *TachoMotor) transaction() *transaction { return m.tx }
+
+// Transaction stages the attribute writes made by calling methods of m in fn
+// and then applies them to the DCMotor in order. No attribute is written
+// unless all the method calls in fn succeed. Before applying the writes, the
+// current values of the written attributes are read, and if a write fails,
+// the attributes that were already written are restored to those values in
+// reverse order. Commands issued in fn are applied in order with the other
+// writes but cannot be undone.
+//
+// Attribute reads made in fn do not observe the staged writes. A Transaction
+// called within fn joins the enclosing transaction.
+func (m *DCMotor) Transaction(fn func(m *DCMotor)) *DCMotor {
+	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err)
+		}
+		return m
+	}
+	err := transact(m, &m.tx, &m.err, func() { fn(m) })
+	if err != nil {
+		m.err = err
+	}
+	return m
+}
+
+func (m *DCMotor) transaction() *transaction { return m.tx }
+
+// Transaction stages the attribute writes made by calling methods of m in fn
+// and then applies them to the ServoMotor in order. No attribute is written
+// unless all the method calls in fn succeed. Before applying the writes, the
+// current values of the written attributes are read, and if a write fails,
+// the attributes that were already written are restored to those values in
+// reverse order. Commands issued in fn are applied in order with the other
+// writes but cannot be undone.
+//
+// Attribute reads made in fn do not observe the staged writes. A Transaction
+// called within fn joins the enclosing transaction.
+func (m *ServoMotor) Transaction(fn func(m *ServoMotor)) *ServoMotor {
+	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err)
+		}
+		return m
+	}
+	err := transact(m, &m.tx, &m.err, func() { fn(m) })
+	if err != nil {
+		m.err = err
+	}
+	return m
+}
+
+func (m *ServoMotor) transaction() *transaction { return m.tx }
+
+// Transaction stages the attribute writes made by calling methods of m in fn
+// and then applies them to the LinearActuator in order. No attribute is
+// written unless all the method calls in fn succeed. Before applying the
+// writes, the current values of the written attributes are read, and if a
+// write fails, the attributes that were already written are restored to those
+// values in reverse order. Commands issued in fn are applied in order with the
+// other writes but cannot be undone.
+//
+// Attribute reads made in fn do not observe the staged writes. A Transaction
+// called within fn joins the enclosing transaction.
+func (m *LinearActuator) Transaction(fn func(m *LinearActuator)) *LinearActuator {
+	if m.err != nil {
+		if diagnose {
+			m.err = skipped(m.err)
+		}
+		return m
+	}
+	err := transact(m, &m.tx, &m.err, func() { fn(m) })
+	if err != nil {
+		m.err = err
+	}
+	return m
+}
+
+func (m *LinearActuator) transaction() *transaction { return m.tx }
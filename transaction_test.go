@@ -0,0 +1,242 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	motor := filepath.Join(TachoMotorPath, "motor0")
+	root, done := fakeRoot(t, map[string]string{
+		filepath.Join(motor, address):           "ev3-ports:outA\n",
+		filepath.Join(motor, driverName):        "lego-ev3-l-motor\n",
+		filepath.Join(motor, countPerRot):       "360\n",
+		filepath.Join(motor, maxSpeed):          "1050\n",
+		filepath.Join(motor, commands):          "run-to-abs-pos stop reset\n",
+		filepath.Join(motor, stopActions):       "coast brake hold\n",
+		filepath.Join(motor, speedSetpoint):     "0\n",
+		filepath.Join(motor, positionSetpoint):  "0\n",
+		filepath.Join(motor, dutyCycleSetpoint): "0\n",
+		filepath.Join(motor, command):           "",
+	})
+	defer done()
+
+	m, err := TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	check := func(attr, want string) {
+		t.Helper()
+		got, err := ioutil.ReadFile(filepath.Join(root, motor, attr))
+		if err != nil {
+			t.Fatalf("unexpected error reading %s: %v", attr, err)
+		}
+		if string(chomp(got)) != want {
+			t.Errorf("unexpected value for %s: got:%q want:%q", attr, got, want)
+		}
+	}
+
+	// A successful transaction applies all writes.
+	err = m.Transaction(func(m *TachoMotor) {
+		m.SetSpeedSetpoint(300).SetPositionSetpoint(90).Command("run-to-abs-pos")
+	}).Err()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	check(speedSetpoint, "300")
+	check(positionSetpoint, "90")
+	check(command, "run-to-abs-pos")
+
+	// An invalid value prevents any write.
+	err = m.Transaction(func(m *TachoMotor) {
+		m.SetSpeedSetpoint(500).SetDutyCycleSetpoint(200).Command("run-to-abs-pos")
+	}).Err()
+	if _, ok := err.(valueOutOfRangeError); !ok {
+		t.Errorf("unexpected error: got:%v want a valueOutOfRangeError", err)
+	}
+	check(speedSetpoint, "300")
+	check(dutyCycleSetpoint, "0")
+
+	// A failed write restores the written attributes.
+	err = os.Remove(filepath.Join(root, motor, command))
+	if err != nil {
+		t.Fatalf("failed to remove command attribute: %v", err)
+	}
+	err = os.Mkdir(filepath.Join(root, motor, command), 0755)
+	if err != nil {
+		t.Fatalf("failed to make command attribute unwritable: %v", err)
+	}
+	err = m.Transaction(func(m *TachoMotor) {
+		m.SetSpeedSetpoint(500).SetPositionSetpoint(-90).Command("run-to-abs-pos")
+	}).Err()
+	if _, ok := err.(*AttrError); !ok {
+		t.Errorf("unexpected error: got:%v want an *AttrError", err)
+	}
+	check(speedSetpoint, "300")
+	check(positionSetpoint, "90")
+
+	// Writes are not staged outside a transaction.
+	err = m.SetSpeedSetpoint(700).Err()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	check(speedSetpoint, "700")
+}
+
+func TestTransactionStopAll(t *testing.T) {
+	motor := filepath.Join(TachoMotorPath, "motor0")
+	root, done := fakeRoot(t, map[string]string{
+		filepath.Join(motor, address):       "ev3-ports:outA\n",
+		filepath.Join(motor, driverName):    "lego-ev3-l-motor\n",
+		filepath.Join(motor, countPerRot):   "360\n",
+		filepath.Join(motor, maxSpeed):      "1050\n",
+		filepath.Join(motor, commands):      "run-forever stop reset\n",
+		filepath.Join(motor, stopActions):   "coast brake hold\n",
+		filepath.Join(motor, speedSetpoint): "0\n",
+		filepath.Join(motor, command):       "",
+	})
+	defer done()
+
+	resLock.Lock()
+	savedResources := resources
+	resources = map[string]map[string]Device{
+		InputClass:  make(map[string]Device),
+		OutputClass: make(map[string]Device),
+		PortClass:   make(map[string]Device),
+	}
+	resLock.Unlock()
+	defer func() {
+		resLock.Lock()
+		resources = savedResources
+		resLock.Unlock()
+	}()
+
+	m, err := TachoMotorFor("ev3-ports:outA", "lego-ev3-l-motor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resources[OutputClass]["ev3-ports:outA"] = m
+
+	read := func(attr string) string {
+		t.Helper()
+		got, err := ioutil.ReadFile(filepath.Join(root, motor, attr))
+		if err != nil {
+			t.Fatalf("unexpected error reading %s: %v", attr, err)
+		}
+		return string(chomp(got))
+	}
+
+	// A StopAll during a transaction is not staged.
+	err = m.Transaction(func(m *TachoMotor) {
+		m.SetSpeedSetpoint(300)
+		err := StopAll()
+		if err != nil {
+			t.Errorf("unexpected error stopping motors: %v", err)
+		}
+		if got := read(command); got != "stop" {
+			t.Errorf("unexpected command during transaction: got:%q want:%q", got, "stop")
+		}
+		if got := read(speedSetpoint); got != "0" {
+			t.Errorf("unexpected speed setpoint during transaction: got:%q want:%q", got, "0")
+		}
+	}).Err()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := read(speedSetpoint); got != "300" {
+		t.Errorf("unexpected speed setpoint after transaction: got:%q want:%q", got, "300")
+	}
+}
+
+func TestTransactionRebind(t *testing.T) {
+	const (
+		addr   = "ev3-ports:outA"
+		driver = "lego-ev3-l-motor"
+	)
+	motor := func(name string) map[string]string {
+		dir := filepath.Join(TachoMotorPath, name)
+		return map[string]string{
+			filepath.Join(dir, address):          addr + "\n",
+			filepath.Join(dir, driverName):       driver + "\n",
+			filepath.Join(dir, countPerRot):      "360\n",
+			filepath.Join(dir, maxSpeed):         "1050\n",
+			filepath.Join(dir, commands):         "run-to-abs-pos stop reset\n",
+			filepath.Join(dir, stopActions):      "coast brake hold\n",
+			filepath.Join(dir, speedSetpoint):    "0\n",
+			filepath.Join(dir, positionSetpoint): "0\n",
+			filepath.Join(dir, stopAction):       "coast\n",
+			filepath.Join(dir, position):         "0\n",
+		}
+	}
+	root, done := fakeRoot(t, motor("motor0"))
+	defer done()
+
+	m, err := ResilientTachoMotorFor(addr, driver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer Release(m)
+	err = m.SetStopAction("hold").Err()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	read := func(attr string) string {
+		t.Helper()
+		got, err := ioutil.ReadFile(filepath.Join(root, TachoMotorPath, "motor1", attr))
+		if err != nil {
+			t.Fatalf("unexpected error reading %s: %v", attr, err)
+		}
+		return string(chomp(got))
+	}
+
+	err = m.Transaction(func(m *TachoMotor) {
+		m.SetSpeedSetpoint(300)
+
+		// Simulate disconnection and reconnection,
+		// and rebind with a read.
+		err := os.RemoveAll(filepath.Join(root, TachoMotorPath, "motor0"))
+		if err != nil {
+			t.Fatalf("failed to remove motor: %v", err)
+		}
+		writeTree(t, root, motor("motor1"))
+		_, err = m.Position()
+		if err != nil {
+			t.Errorf("unexpected error after reconnection: %v", err)
+		}
+
+		// The recorded configuration is written
+		// immediately and later writes are staged.
+		m.SetPositionSetpoint(90)
+		if got := read(stopAction); got != "hold" {
+			t.Errorf("unexpected stop action during transaction: got:%q want:%q", got, "hold")
+		}
+		for _, attr := range []string{speedSetpoint, positionSetpoint} {
+			if got := read(attr); got != "0" {
+				t.Errorf("unexpected %s during transaction: got:%q want:%q", attr, got, "0")
+			}
+		}
+	}).Err()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := m.String(); got != "motor1" {
+		t.Errorf("unexpected rebound motor: got:%s want:motor1", got)
+	}
+	for _, test := range []struct{ attr, want string }{
+		{attr: speedSetpoint, want: "300"},
+		{attr: positionSetpoint, want: "90"},
+		{attr: stopAction, want: "hold"},
+	} {
+		if got := read(test.attr); got != test.want {
+			t.Errorf("unexpected %s after transaction: got:%q want:%q", test.attr, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Wait blocks until the wanted motor state under the motor state mask is
+// reached, or the timeout is reached. If timeout is negative Wait will wait
+// indefinitely for the wanted motor state has been reached.
+// The last unmasked motor state is returned unless the timeout was reached
+// before the motor state was read.
+// When the any parameter is false, Wait will return ok as true if
+//  (state&mask)^not == want|not
+// and when any is true Wait return false if
+//  (state&mask)^not != 0 && state&mask&not == 0 .
+// Otherwise ok will return false indicating that the returned state did
+// not match the request.
+// Wait will not set the error state of the StaterDevice, but will clear and
+// return it if it is not nil.
+func Wait(d StaterDevice, mask, want, not MotorState, any bool, timeout time.Duration) (stat MotorState, ok bool, err error) {
+	// We use a direct implementation of the State method here
+	// to ensure we are polling on the same file as we are reading
+	// from. Also, since we are potentially probing the state
+	// repeatedly, we save file opens.
+	//
+	// This also allows us to test the code, which would not
+	// otherwise be possible since sysiphus cannot do POLLPRI
+	// polling, due to limitations in FUSE.
+	//
+	// On systems without poll(2), and when polling is disabled,
+	// the state is read at intervals until the timeout is reached.
+
+	// Check if we can proceed.
+	err = d.Err()
+	if err != nil {
+		return 0, false, err
+	}
+
+	// When device I/O is recorded or replayed, f
+	// is nil and the state is read as an attribute.
+	var f *os.File
+	if sess == nil {
+		f, err = os.Open(filepath.Join(d.Path(), d.String(), state))
+		if err != nil {
+			return 0, false, err
+		}
+		defer f.Close()
+	}
+
+	// See if we can exit early.
+	stat, err = motorState(d, f)
+	if err != nil {
+		return stat, false, err
+	}
+	if stateIsOK(stat, mask, want, not, any) {
+		return stat, true, nil
+	}
+
+	clk := clock
+	p := newStatePoller([]*os.File{f})
+
+	end := clk.Now().Add(timeout)
+	for timeout < 0 || clk.Now().Before(end) {
+		if p != nil {
+			_timeout := timeout
+			if timeout >= 0 {
+				if remain := end.Sub(clk.Now()); remain < timeout {
+					_timeout = remain
+				}
+			}
+			n, err := p.poll(_timeout)
+			if n == 0 {
+				return 0, false, err
+			}
+		}
+		stat, err = motorState(d, f)
+		if err != nil {
+			return stat, false, err
+		}
+		if stateIsOK(stat, mask, want, not, any) {
+			return stat, true, nil
+		}
+
+		relax := 50 * time.Millisecond
+		if timeout >= 0 {
+			if remain := end.Sub(clk.Now()); remain < relax {
+				relax = remain / 2
+			}
+		}
+		clk.Sleep(relax)
+	}
+
+	return stat, false, nil
+}
+
+// WaitContext blocks until the wanted motor state under the motor state mask
+// is reached, or the context is done. The mask, want, not and any parameters
+// have the same semantics as for Wait. If the context is done before the
+// wanted state is reached, the last read motor state is returned with ok
+// false and the context's error.
+// WaitContext will not set the error state of the StaterDevice, but will clear
+// and return it if it is not nil.
+func WaitContext(ctx context.Context, d StaterDevice, mask, want, not MotorState, any bool) (stat MotorState, ok bool, err error) {
+	stats, oks, err := WaitDevices(ctx, true, StateCondition{Device: d, Mask: mask, Want: want, Not: not, Any: any})
+	return stats[0], oks[0], err
+}
+
+// WaitDevices blocks until all, or if all is false any, of the given conditions
+// are satisfied, or the context is done. The state files of all the devices are
+// waited on together.
+// The returned stats and ok hold the last read motor state of each device and
+// whether the corresponding condition was satisfied by that state. If the context
+// is done before the conditions are satisfied, the context's error is returned.
+// WaitDevices will not set the error state of the StaterDevices, but will clear
+// and return the first non-nil error state found.
+func WaitDevices(ctx context.Context, all bool, conds ...StateCondition) (stats []MotorState, ok []bool, err error) {
+	stats = make([]MotorState, len(conds))
+	ok = make([]bool, len(conds))
+	if len(conds) == 0 {
+		return stats, ok, nil
+	}
+
+	// Check if we can proceed.
+	for _, c := range conds {
+		err = c.Device.Err()
+		if err != nil {
+			return stats, ok, err
+		}
+	}
+
+	files := make([]*os.File, len(conds))
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+	for i, c := range conds {
+		if sess != nil {
+			// The state is read as an attribute.
+			continue
+		}
+		files[i], err = os.Open(filepath.Join(c.Device.Path(), c.Device.String(), state))
+		if err != nil {
+			return stats, ok, err
+		}
+	}
+
+	check := func() (bool, error) {
+		var n int
+		for i, f := range files {
+			var err error
+			stats[i], err = motorState(conds[i].Device, f)
+			if err != nil {
+				return false, err
+			}
+			ok[i] = conds[i].isOK(stats[i])
+			if ok[i] {
+				n++
+			}
+		}
+		if all {
+			return n == len(conds), nil
+		}
+		return n != 0, nil
+	}
+
+	// See if we can exit early.
+	done, err := check()
+	if err != nil || done {
+		return stats, ok, err
+	}
+
+	const relax = 50 * time.Millisecond
+
+	clk := clock
+	p := newStatePoller(files)
+
+	for {
+		if p != nil {
+			// Limit the poll timeout so that
+			// we notice context cancellation.
+			timeout := relax
+			if deadline, has := ctx.Deadline(); has {
+				if remain := time.Until(deadline); remain < timeout {
+					timeout = remain
+				}
+			}
+			if timeout < 0 {
+				timeout = 0
+			}
+			_, err := p.poll(timeout)
+			if err != nil && !isInterrupted(err) {
+				return stats, ok, err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return stats, ok, ctx.Err()
+		default:
+		}
+		done, err = check()
+		if err != nil || done {
+			return stats, ok, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return stats, ok, ctx.Err()
+		case <-clk.After(relax):
+		}
+	}
+}
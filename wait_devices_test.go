@@ -0,0 +1,97 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWaitDevices(t *testing.T) {
+	root, done := fakeRoot(t, nil)
+	defer done()
+
+	motors := []*TachoMotor{{id: 0}, {id: 1}}
+	setState := func(m *TachoMotor, s string) {
+		writeTree(t, root, map[string]string{filepath.Join(TachoMotorPath, m.String(), state): s + "\n"})
+	}
+	stopped := func(m *TachoMotor) StateCondition {
+		return StateCondition{Device: m, Mask: Running}
+	}
+
+	for _, test := range []struct {
+		all    bool
+		wantOK []bool
+	}{
+		{all: true, wantOK: []bool{true, true}},
+		{all: false, wantOK: []bool{true, false}},
+	} {
+		setState(motors[0], running)
+		setState(motors[1], running)
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			setState(motors[0], "")
+			time.Sleep(200 * time.Millisecond)
+			setState(motors[1], holding)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		stats, ok, err := WaitDevices(ctx, test.all, stopped(motors[0]), stopped(motors[1]))
+		cancel()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(ok, test.wantOK) {
+			t.Errorf("unexpected ok for all=%t: got:%v want:%v", test.all, ok, test.wantOK)
+		}
+		if stats[0] != 0 {
+			t.Errorf("unexpected state for %v: got:%v want:none", motors[0], stats[0])
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	setState(motors[0], running)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	stat, ok, err := WaitContext(ctx, motors[0], Running, 0, 0, false)
+	if err != context.DeadlineExceeded {
+		t.Errorf("unexpected error: got:%v want:%v", err, context.DeadlineExceeded)
+	}
+	if ok {
+		t.Error("unexpected success waiting for running motor to stop")
+	}
+	if stat != Running {
+		t.Errorf("unexpected state: got:%v want:%v", stat, Running)
+	}
+
+	stat, ok, err = Wait(motors[0], Running, 0, 0, false, 100*time.Millisecond)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("unexpected success waiting for running motor to stop")
+	}
+	if stat != Running {
+		t.Errorf("unexpected state: got:%v want:%v", stat, Running)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		setState(motors[0], "")
+	}()
+	stat, ok, err = Wait(motors[0], Running, 0, 0, false, 5*time.Second)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("unexpected failure waiting for motor to stop")
+	}
+	if stat != 0 {
+		t.Errorf("unexpected state: got:%v want:none", stat)
+	}
+}
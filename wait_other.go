@@ -7,24 +7,28 @@
 package ev3dev
 
 import (
+	"os"
 	"time"
 )
 
-// Wait blocks until the wanted motor state under the motor state mask is
-// reached, or the timeout is reached. If timeout is negative Wait will wait
-// indefinitely for the wanted motor state has been reached.
-// The last unmasked motor state is returned unless the timeout was reached
-// before the motor state was read.
-// When the any parameter is false, Wait will return ok as true if
-//  (state&mask)^not == want|not
-// and when any is true Wait return false if
-//  (state&mask)^not != 0 && state&mask&not == 0 .
-// Otherwise ok will return false indicating that the returned state did
-// not match the request.
-// Wait will not set the error state of the StaterDevice, but will clear and
-// return it if it is not nil.
+// statePoller waits for changes in motor state files.
 //
-// Wait is not implemented without a linux OS (needs unix.Poll).
-func Wait(d StaterDevice, mask, want, not MotorState, any bool, timeout time.Duration) (stat MotorState, ok bool, err error) {
-	panic("ev3dev: needs GOOS=linux")
+// Without a linux OS poll(2) is not used, so a statePoller is never
+// returned by newStatePoller and motor state files are read at
+// intervals.
+type statePoller struct{}
+
+// newStatePoller returns nil since polling is not available.
+func newStatePoller(files []*os.File) *statePoller {
+	return nil
+}
+
+// poll is never called since newStatePoller returns nil.
+func (p *statePoller) poll(timeout time.Duration) (int, error) {
+	panic("ev3dev: unexpected call of statePoller poll")
+}
+
+// isInterrupted returns false.
+func isInterrupted(err error) bool {
+	return false
 }
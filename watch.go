@@ -0,0 +1,131 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// watchInterval is the interval between reads of a watched attribute
+// when the attribute does not notify changes.
+const watchInterval = 50 * time.Millisecond
+
+// AttributeEvent is a change in the value of a watched attribute. The Err
+// value reflects any error state arising from reading the attribute.
+type AttributeEvent struct {
+	Value string
+	Time  time.Time
+	Err   error
+}
+
+// AttributeWatcher provides a mechanism to block waiting for changes in the
+// value of a device attribute.
+type AttributeWatcher struct {
+	Events <-chan AttributeEvent
+
+	f       *os.File
+	done    chan struct{}
+	wg      sync.WaitGroup
+	closing sync.Once
+}
+
+// WatchAttribute returns an AttributeWatcher for the named attribute of the
+// device d. The first event sent on the watcher's Events channel holds the
+// value of the attribute when the watch was started, and subsequent events
+// are sent when the value changes.
+//
+// Attributes that support sysfs_notify, such as the lego-port status and the
+// tacho-motor state attributes, are waited on with poll(2) and changes are
+// delivered as soon as they are notified. Other attributes, and attributes on
+// systems where poll is not available, are read every 50ms.
+//
+// WatchAttribute will not set the error state of the Device, but will clear
+// and return it if it is not nil.
+func WatchAttribute(d Device, attr string) (*AttributeWatcher, error) {
+	err := d.Err()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(d.Path(), d.String(), attr)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, newAttrOpError(d, attr, "", "watch", err)
+	}
+
+	c := make(chan AttributeEvent)
+	w := &AttributeWatcher{Events: c, f: f, done: make(chan struct{})}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer close(c)
+
+		var (
+			last  string
+			first = true
+			buf   = make([]byte, 4096)
+		)
+		for {
+			val, err := readValue(f, buf)
+			if err != nil {
+				err = newAttrOpError(d, attr, "", "read", err)
+			}
+			if first || err != nil || val != last {
+				select {
+				case c <- AttributeEvent{Value: val, Time: clock.Now(), Err: err}:
+				case <-w.done:
+					return
+				}
+				first = false
+				if err == nil {
+					last = val
+				}
+			}
+
+			select {
+			case <-w.done:
+				return
+			default:
+			}
+			waitChange(f, watchInterval)
+		}
+	}()
+	return w, nil
+}
+
+// readValue reads the chomped value of the attribute file f using buf.
+// Reading the file re-arms sysfs notification.
+func readValue(f *os.File, buf []byte) (string, error) {
+	n, err := f.ReadAt(buf, 0)
+	if n == len(buf) && err == nil {
+		return "", errors.New("ev3dev: buffer full")
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if n == 0 {
+		return "", nil
+	}
+	return string(chomp(buf[:n])), nil
+}
+
+// Close stops the AttributeWatcher and closes the Events channel. Only the
+// first call to Close has any effect.
+func (w *AttributeWatcher) Close() error {
+	var err error
+	w.closing.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+		err = w.f.Close()
+	})
+	return err
+}
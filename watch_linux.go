@@ -0,0 +1,33 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build linux
+
+package ev3dev
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// waitChange waits until f is notified as changed by sysfs or the timeout
+// has elapsed.
+func waitChange(f *os.File, timeout time.Duration) {
+	if !canPoll {
+		time.Sleep(timeout)
+		return
+	}
+	fds := []unix.PollFd{{Fd: int32(f.Fd()), Events: unix.POLLPRI | unix.POLLERR}}
+	n, err := unix.Poll(fds, int(timeout/time.Millisecond))
+	if n == 0 && err == nil {
+		// The timeout has elapsed.
+		return
+	}
+	if err != nil || fds[0].Revents&unix.POLLPRI == 0 {
+		// The file does not support poll.
+		time.Sleep(timeout)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !linux
+
+package ev3dev
+
+import (
+	"os"
+	"time"
+)
+
+// waitChange waits until the timeout has elapsed.
+func waitChange(_ *os.File, timeout time.Duration) {
+	time.Sleep(timeout)
+}
@@ -0,0 +1,110 @@
+// Copyright ©2026 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchAttribute(t *testing.T) {
+	root, done := fakeRoot(t, nil)
+	defer done()
+
+	p := &LegoPort{id: 0}
+	setStatus := func(s string) {
+		writeTree(t, root, map[string]string{filepath.Join(LegoPortPath, p.String(), status): s + "\n"})
+	}
+	setStatus("no-motor")
+
+	w, err := WatchAttribute(p, status)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	next := func() AttributeEvent {
+		for {
+			select {
+			case ev := <-w.Events:
+				if ev.Value == "" && ev.Err == nil {
+					// Skip reads made while the
+					// attribute was being rewritten.
+					continue
+				}
+				return ev
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for event")
+				return AttributeEvent{}
+			}
+		}
+	}
+
+	for i, want := range []string{"no-motor", "tacho-motor", "no-motor"} {
+		if i != 0 {
+			setStatus(want)
+		}
+		ev := next()
+		if ev.Err != nil {
+			t.Errorf("unexpected error: %v", ev.Err)
+		}
+		if ev.Value != want {
+			t.Errorf("unexpected value: got:%q want:%q", ev.Value, want)
+		}
+	}
+
+	// Rewriting the same value is not a change.
+	setStatus("no-motor")
+	timeout := time.After(3 * watchInterval)
+wait:
+	for {
+		select {
+		case ev := <-w.Events:
+			if ev.Value != "" && ev.Value != "no-motor" {
+				t.Errorf("unexpected event for unchanged value: %+v", ev)
+			}
+		case <-timeout:
+			break wait
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Errorf("unexpected error closing watcher: %v", err)
+	}
+	if _, ok := <-w.Events; ok {
+		t.Error("expected closed Events channel")
+	}
+}
+
+func TestAttributeWatcherConcurrentClose(t *testing.T) {
+	p := &LegoPort{id: 0}
+	_, done := fakeRoot(t, map[string]string{
+		filepath.Join(LegoPortPath, p.String(), status): "no-motor\n",
+	})
+	defer done()
+
+	w, err := WatchAttribute(p, status)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := w.Close()
+			if err != nil {
+				t.Errorf("unexpected error closing watcher: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	for range w.Events {
+	}
+}